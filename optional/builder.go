@@ -0,0 +1,42 @@
+package optional
+
+import "fmt"
+
+//*********************************************************************************
+//                                 struct Builder
+//*********************************************************************************
+
+// Accumulates a value field by field from steps that can each fail.
+// The zero value is ready to use.
+//
+//	var b Builder
+//	b.Field("Host", func() Optional[Void] { ... }).
+//		Field("Port", func() Optional[Void] { ... })
+//	if res := b.Build(); res.IsError() { return Cast[Config](res) }
+type Builder struct {
+	steps []builderStep
+}
+
+type builderStep struct {
+	name string
+	set  func() Optional[Void]
+}
+
+// Register a named step. Steps are run by Build in the order they were registered.
+func (b *Builder) Field(name string, set func() Optional[Void]) *Builder {
+	b.steps = append(b.steps, builderStep{name: name, set: set})
+	return b
+}
+
+// Run all registered steps in order, stopping at the first error.
+// The error message is prefixed with the name of the failing step, the error code is kept
+// and the original error stays reachable through errors.Unwrap.
+func (b *Builder) Build() Optional[Void] {
+	for _, step := range b.steps {
+		res := step.set()
+		if res.IsError() {
+			return Optional[Void]{Error: fmt.Errorf("%s: %w", step.name, res.Error), ErrorCode: res.ErrorCode}
+		}
+	}
+	return OkVoid()
+}
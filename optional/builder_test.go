@@ -0,0 +1,38 @@
+package optional
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("Build runs all steps", func(t *testing.T) {
+		type config struct{ Host string; Port int }
+		var cfg config
+		var b Builder
+		res := b.Field("Host", func() Optional[Void] { cfg.Host = "localhost"; return OkVoid() }).
+			Field("Port", func() Optional[Void] { cfg.Port = 8080; return OkVoid() }).
+			Build()
+		if res.IsError() { t.Fatalf("unexpected error: %v", res.Error) }
+		if cfg.Host != "localhost" || cfg.Port != 8080 { t.Fatalf("fields not set: %+v", cfg) }
+	})
+
+	t.Run("Build stops at first error with field name", func(t *testing.T) {
+		cause := errors.New("not a number")
+		ran := false
+		var b Builder
+		res := b.Field("Host", func() Optional[Void] { return OkVoid() }).
+			Field("Port", func() Optional[Void] { return CodeErr[Void](12, cause) }).
+			Field("Timeout", func() Optional[Void] { ran = true; return OkVoid() }).
+			Build()
+		if ran { t.Fatalf("steps after the failing one must not run") }
+		if res.ErrorCode != 12 { t.Fatalf("expected code 12, got %d", res.ErrorCode) }
+		if res.Error.Error() != "Port: not a number" { t.Fatalf("unexpected message: %v", res.Error) }
+		if !errors.Is(res.Error, cause) { t.Fatalf("cause not wrapped") }
+	})
+
+	t.Run("Empty builder succeeds", func(t *testing.T) {
+		var b Builder
+		if res := b.Build(); res.IsError() || !res.IsSome() { t.Fatalf("expected OkVoid, got %v", res) }
+	})
+}
@@ -0,0 +1,87 @@
+package optional
+
+import (
+	"fmt"
+	"sync"
+)
+
+//*********************************************************************************
+//                                struct CodeSpace
+//*********************************************************************************
+
+// A range of error codes [base, base+size) allocated by one module.
+// Ranges of different modules never overlap and never contain 0 or the reserved codes
+// from RESERVED_CODE_MIN up to PANIC_CODE.
+//
+//	var authCodes = NewCodeSpace("auth", 1000, 100)
+//	var INVALID_TOKEN = authCodes.Define(1, "INVALID_TOKEN")
+//	...
+//	return CodeErr[User](INVALID_TOKEN, err)
+type CodeSpace struct {
+	name  string
+	base  uint32
+	size  uint32
+	names map[uint32]string
+}
+
+var codeSpacesMu sync.RWMutex
+var codeSpaces []*CodeSpace
+
+// Allocate the codes [base, base+size) for the module name.
+// Panics if the range is empty, contains 0 or reserved codes, or overlaps an already allocated range.
+// Meant to be called during package initialization.
+func NewCodeSpace(name string, base uint32, size uint32) *CodeSpace {
+	if size == 0 || base == 0 || uint64(base)+uint64(size) > RESERVED_CODE_MIN {
+		panic(fmt.Sprintf("NewCodeSpace(%q): range [%d, %d) is empty or contains reserved codes", name, base, uint64(base)+uint64(size)))
+	}
+	codeSpacesMu.Lock()
+	defer codeSpacesMu.Unlock()
+	for _, other := range codeSpaces {
+		if base < other.base+other.size && other.base < base+size {
+			panic(fmt.Sprintf("NewCodeSpace(%q): range [%d, %d) overlaps %q [%d, %d)", name, base, base+size, other.name, other.base, other.base+other.size))
+		}
+	}
+	cs := &CodeSpace{name: name, base: base, size: size, names: map[uint32]string{}}
+	codeSpaces = append(codeSpaces, cs)
+	return cs
+}
+
+// Get the code at offset within the space. Panics if offset is outside the space.
+func (cs *CodeSpace) Code(offset uint32) uint32 {
+	if offset >= cs.size {
+		panic(fmt.Sprintf("CodeSpace %q: offset %d exceeds size %d", cs.name, offset, cs.size))
+	}
+	return cs.base + offset
+}
+
+// Get the code at offset and register its name for CodeName. Panics if offset is outside the space.
+func (cs *CodeSpace) Define(offset uint32, name string) uint32 {
+	code := cs.Code(offset)
+	codeSpacesMu.Lock()
+	defer codeSpacesMu.Unlock()
+	cs.names[code] = name
+	return code
+}
+
+// Describe a code by its space and name, e.g. "auth.INVALID_TOKEN" or "auth+7" for codes without name.
+// Returns an empty string for codes outside of any allocated space.
+func CodeName(code uint32) string {
+	codeSpacesMu.RLock()
+	defer codeSpacesMu.RUnlock()
+	for _, cs := range codeSpaces {
+		if code >= cs.base && code-cs.base < cs.size {
+			if name, ok := cs.names[code]; ok {
+				return cs.name + "." + name
+			}
+			return fmt.Sprintf("%s+%d", cs.name, code-cs.base)
+		}
+	}
+	return ""
+}
+
+// Drop all allocated code spaces, for tests.
+func resetCodeSpaces() {
+	codeSpacesMu.Lock()
+	defer codeSpacesMu.Unlock()
+	codeSpaces = nil
+}
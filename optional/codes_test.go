@@ -0,0 +1,38 @@
+package optional
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCodes(t *testing.T) {
+	mustPanicWith := func(t *testing.T, want string, f func()) {
+		t.Helper()
+		if r := mustPanic(t, f); !strings.Contains(fmt.Sprint(r), want) { t.Fatalf("expected panic containing %q, got %v", want, r) }
+	}
+
+	t.Run("CodeSpace allocates codes", func(t *testing.T) {
+		t.Cleanup(resetCodeSpaces)
+		auth := NewCodeSpace("auth", 1000, 100)
+		invalidToken := auth.Define(1, "INVALID_TOKEN")
+		if invalidToken != 1001 { t.Fatalf("expected 1001, got %d", invalidToken) }
+		if auth.Code(7) != 1007 { t.Fatalf("expected 1007, got %d", auth.Code(7)) }
+		if opt := CodeErr[int](invalidToken, "bad token"); opt.ErrorCode != 1001 { t.Fatalf("expected code 1001, got %d", opt.ErrorCode) }
+		if name := CodeName(invalidToken); name != "auth.INVALID_TOKEN" { t.Fatalf("unexpected name %q", name) }
+		if name := CodeName(1007); name != "auth+7" { t.Fatalf("unexpected name %q", name) }
+		if name := CodeName(999); name != "" { t.Fatalf("expected no name outside spaces, got %q", name) }
+		mustPanicWith(t, "exceeds size", func() { auth.Code(100) })
+	})
+
+	t.Run("CodeSpace rejects overlaps and reserved codes", func(t *testing.T) {
+		t.Cleanup(resetCodeSpaces)
+		NewCodeSpace("db", 2000, 50)
+		mustPanicWith(t, "overlaps", func() { NewCodeSpace("cache", 2049, 10) })
+		mustPanicWith(t, "reserved", func() { NewCodeSpace("zero", 0, 10) })
+		mustPanicWith(t, "empty", func() { NewCodeSpace("empty", 3000, 0) })
+		mustPanicWith(t, "reserved", func() { NewCodeSpace("reserved", RESERVED_CODE_MIN-5, 10) })
+		mustPanicWith(t, "reserved", func() { NewCodeSpace("panic", PANIC_CODE, 1) })
+		if cs := NewCodeSpace("below reserved", RESERVED_CODE_MIN-10, 10); cs.Code(9) != RESERVED_CODE_MIN-1 { t.Fatalf("expected range up to RESERVED_CODE_MIN to be allowed") }
+	})
+}
@@ -0,0 +1,63 @@
+package optional
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+//*********************************************************************************
+//                               Converter Registry
+//*********************************************************************************
+
+type converterKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+var convertersMu sync.RWMutex
+var converters = map[converterKey]func(any) (any, error){}
+
+// Register a conversion from From to To used by ConvertVia, e.g. string to uuid.UUID.
+// Replaces a previously registered conversion for the same pair of types.
+// Meant to be called during package initialization.
+func RegisterConverter[From any, To any](f func(From) (To, error)) {
+	key := converterKey{from: reflect.TypeFor[From](), to: reflect.TypeFor[To]()}
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[key] = func(v any) (any, error) { return f(v.(From)) }
+}
+
+// Drop all registered conversions, for tests.
+func resetConverters() {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters = map[converterKey]func(any) (any, error){}
+}
+
+// Convert the contained value with the converter registered for its dynamic type and T,
+// falling back to a type assertion like Cast. Errors and empty Optionals are passed through.
+// A failing converter or a missing conversion returns an error with CONVERSION_ERROR_CODE.
+func ConvertVia[T any, U any](o Optional[U]) Optional[T] {
+	if o.IsError() {
+		return Forward[T](o)
+	}
+	if !o.present {
+		return None[T]()
+	}
+	value := any(o.Value)
+	convertersMu.RLock()
+	convert := converters[converterKey{from: reflect.TypeOf(value), to: reflect.TypeFor[T]()}]
+	convertersMu.RUnlock()
+	if convert != nil {
+		converted, err := convert(value)
+		if err != nil {
+			return codeErr[T](CONVERSION_ERROR_CODE, fmt.Errorf("ConvertVia[%v](%T): %w", reflect.TypeFor[T](), value, err))
+		}
+		return Ok(converted.(T))
+	}
+	if converted, ok := value.(T); ok {
+		return Ok(converted)
+	}
+	return codeErr[T](CONVERSION_ERROR_CODE, fmt.Errorf("ConvertVia[%v](%T): no converter registered", reflect.TypeFor[T](), value))
+}
@@ -0,0 +1,37 @@
+package optional
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type convertTestID [2]byte
+
+func TestConvert(t *testing.T) {
+	t.Cleanup(resetConverters)
+	RegisterConverter(func(s string) (convertTestID, error) {
+		if len(s) != 2 {
+			return convertTestID{}, errors.New("invalid id")
+		}
+		return convertTestID{s[0], s[1]}, nil
+	})
+	RegisterConverter(func(i int) (string, error) { return strconv.Itoa(i), nil })
+
+	t.Run("ConvertVia uses registered converters", func(t *testing.T) {
+		if o := ConvertVia[convertTestID](Ok("ab")); !o.IsSomeStrict() || o.Value != (convertTestID{'a', 'b'}) { t.Fatalf("expected converted id, got %v", o) }
+		if o := ConvertVia[convertTestID](Ok[any]("cd")); o.Value != (convertTestID{'c', 'd'}) { t.Fatalf("expected lookup by dynamic type, got %v", o) }
+		if o := ConvertVia[convertTestID](Ok("abc")); o.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected conversion error, got %v", o) }
+		if o := ConvertVia[string](Ok(42)); o.Value != "42" { t.Fatalf("expected 42, got %v", o) }
+	})
+
+	t.Run("ConvertVia falls back to type assertion", func(t *testing.T) {
+		if o := ConvertVia[int](Ok[any](5)); o.Value != 5 { t.Fatalf("expected 5, got %v", o) }
+		if o := ConvertVia[float64](Ok("x")); o.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected CONVERSION_ERROR_CODE, got %v (%d)", o, o.ErrorCode) }
+	})
+
+	t.Run("ConvertVia passes errors and none through", func(t *testing.T) {
+		if o := ConvertVia[convertTestID](CodeErr[string](9, "e")); o.ErrorCode != 9 { t.Fatalf("expected forwarded error, got %v", o) }
+		if o := ConvertVia[convertTestID](None[string]()); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+	})
+}
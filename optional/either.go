@@ -0,0 +1,66 @@
+package optional
+
+//*********************************************************************************
+//                                  type Either
+//*********************************************************************************
+
+// Holds either a Left or a Right value, for alternatives where neither side is an error.
+// By convention Right is the main result, e.g. a parsed value, and Left the alternative, e.g. a redirect.
+// The zero value is Left holding the zero value of L.
+type Either[L any, R any] struct {
+	left    L
+	right   R
+	isRight bool
+}
+
+// Return an Either holding the Left value v.
+func Left[L any, R any](v L) Either[L, R] {
+	return Either[L, R]{left: v}
+}
+
+// Return an Either holding the Right value v.
+func Right[L any, R any](v R) Either[L, R] {
+	return Either[L, R]{right: v, isRight: true}
+}
+
+// Returns if the Left value is held.
+func (e Either[L, R]) IsLeft() bool {
+	return !e.isRight
+}
+
+// Returns if the Right value is held.
+func (e Either[L, R]) IsRight() bool {
+	return e.isRight
+}
+
+// Get the Left value, or an empty Optional if Right is held.
+func (e Either[L, R]) LeftOptional() Optional[L] {
+	if e.isRight {
+		return None[L]()
+	}
+	return Ok(e.left)
+}
+
+// Get the Right value, or an empty Optional if Left is held.
+func (e Either[L, R]) ToOptional() Optional[R] {
+	if !e.isRight {
+		return None[R]()
+	}
+	return Ok(e.right)
+}
+
+// Map the Right value with f, a Left value is passed through.
+func MapRight[L any, R any, U any](e Either[L, R], f func(R) U) Either[L, U] {
+	if !e.isRight {
+		return Left[L, U](e.left)
+	}
+	return Right[L](f(e.right))
+}
+
+// Map the Left value with f, a Right value is passed through.
+func MapLeft[L any, R any, U any](e Either[L, R], f func(L) U) Either[U, R] {
+	if e.isRight {
+		return Right[U](e.right)
+	}
+	return Left[U, R](f(e.left))
+}
@@ -0,0 +1,31 @@
+package optional
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEither(t *testing.T) {
+	t.Run("Left and Right", func(t *testing.T) {
+		l := Left[string, int]("redirect")
+		if !l.IsLeft() || l.IsRight() { t.Fatalf("expected left") }
+		if o := l.LeftOptional(); o.Value != "redirect" || !o.IsSomeStrict() { t.Fatalf("expected left value, got %v", o) }
+		r := Right[string](0)
+		if !r.IsRight() || r.IsLeft() { t.Fatalf("expected right") }
+		if o := r.LeftOptional(); !o.IsNone() { t.Fatalf("expected no left value, got %v", o) }
+	})
+
+	t.Run("MapRight and MapLeft", func(t *testing.T) {
+		r := MapRight(Right[string](7), strconv.Itoa)
+		if o := r.ToOptional(); o.Value != "7" { t.Fatalf("expected mapped right, got %v", o) }
+		if l := MapLeft(r, func(s string) int { t.Fatalf("f must not run on right"); return 0 }); l.ToOptional().Value != "7" { t.Fatalf("expected right to pass") }
+		l := MapLeft(Left[string, int]("abc"), func(s string) int { return len(s) })
+		if o := l.LeftOptional(); o.Value != 3 { t.Fatalf("expected mapped left, got %v", o) }
+		if m := MapRight(l, func(int) int { t.Fatalf("f must not run on left"); return 0 }); !m.IsLeft() { t.Fatalf("expected left to pass") }
+	})
+
+	t.Run("ToOptional treats Left as absence", func(t *testing.T) {
+		if o := Left[string, int]("x").ToOptional(); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+		if o := Right[string](0).ToOptional(); !o.IsSomeStrict() || o.Value != 0 { t.Fatalf("expected present zero value, got %v", o) }
+	})
+}
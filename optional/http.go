@@ -0,0 +1,40 @@
+package optional
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//*********************************************************************************
+//                                 HTTP Responses
+//*********************************************************************************
+
+// Write the Optional as JSON envelope (see MarshalJSON) to an HTTP response:
+//
+//	value   200 OK with {"value": ...}
+//	error   status from codeMap for the error code, 500 if not mapped, with {"error": ..., "code": ...}
+//	empty   204 No Content without body
+//
+// A best-effort value carried by an error (see GoOpt) is not written.
+func (o Optional[T]) WriteJSON(w http.ResponseWriter, codeMap map[uint32]int) {
+	status := http.StatusOK
+	switch {
+	case o.IsError():
+		o = Forward[T](o)
+		status = http.StatusInternalServerError
+		if mapped, ok := codeMap[o.ErrorCode]; ok {
+			status = mapped
+		}
+	case !o.present:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	body, err := json.Marshal(o)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,39 @@
+package optional
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTP(t *testing.T) {
+	codeMap := map[uint32]int{404: http.StatusNotFound}
+
+	t.Run("WriteJSON value", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Ok(map[string]int{"id": 1}).WriteJSON(rec, codeMap)
+		if rec.Code != http.StatusOK { t.Fatalf("expected 200, got %d", rec.Code) }
+		if rec.Body.String() != `{"value":{"id":1}}` { t.Fatalf("unexpected body %s", rec.Body) }
+		if rec.Header().Get("Content-Type") != "application/json" { t.Fatalf("expected JSON content type") }
+	})
+
+	t.Run("WriteJSON mapped error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		CodeErr[int](404, "user not found").WriteJSON(rec, codeMap)
+		if rec.Code != http.StatusNotFound { t.Fatalf("expected 404, got %d", rec.Code) }
+		if rec.Body.String() != `{"error":"user not found","code":404}` { t.Fatalf("unexpected body %s", rec.Body) }
+	})
+
+	t.Run("WriteJSON unknown code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		GoOpt(5, Err[int]("partial").Error).WriteJSON(rec, codeMap)
+		if rec.Code != http.StatusInternalServerError { t.Fatalf("expected 500, got %d", rec.Code) }
+		if rec.Body.String() != `{"error":"partial"}` { t.Fatalf("best-effort value must not be written, got %s", rec.Body) }
+	})
+
+	t.Run("WriteJSON none", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		None[int]().WriteJSON(rec, nil)
+		if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 { t.Fatalf("expected empty 204, got %d %s", rec.Code, rec.Body) }
+	})
+}
@@ -0,0 +1,151 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+//*********************************************************************************
+//                         Standard Library Interoperability
+//*********************************************************************************
+
+// Expose an Optional as a command line flag, register the result with flag.Var.
+// Set parses the argument with parse and stores the result in o, a parse failure is stored as
+// error Optional and returned to the flag package. An unset flag leaves o untouched.
+// String renders the value, or an empty string on error or if empty.
+func FlagVar[T any](o *Optional[T], parse func(string) (T, error)) flag.Value {
+	return &flagValue[T]{opt: o, parse: parse}
+}
+
+type flagValue[T any] struct {
+	opt   *Optional[T]
+	parse func(string) (T, error)
+}
+
+func (f *flagValue[T]) Set(s string) error {
+	*f.opt = GoOpt(f.parse(s))
+	return f.opt.Error
+}
+
+func (f *flagValue[T]) String() string {
+	if f.opt == nil || f.opt.IsError() || !f.opt.present { // flag package calls String on a zero flagValue
+		return ""
+	}
+	return fmt.Sprintf("%v", f.opt.Value)
+}
+
+// Read an environment variable: a value if it is set (also if set to an empty string), empty if unset.
+func FromEnv(key string) Optional[string] {
+	if value, ok := os.LookupEnv(key); ok {
+		return Ok(value)
+	}
+	return None[string]()
+}
+
+// Read and parse an environment variable: empty if unset, otherwise the result of parse.
+// A parse failure is an error Optional, with the variable name prefixed to the message.
+func FromEnvAs[T any](key string, parse func(string) (T, error)) Optional[T] {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return None[T]()
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return Errf[T]("environment variable %s: %w", key, err)
+	}
+	return Ok(parsed)
+}
+
+// Store an Optional in a context under key, retrieve it with FromCtx.
+func ContextWith[T any](ctx context.Context, key any, o Optional[T]) context.Context {
+	return context.WithValue(ctx, key, o)
+}
+
+// Retrieve an Optional stored by ContextWith: empty if key is absent,
+// an error with CONVERSION_ERROR_CODE if the stored value is not an Optional[T].
+func FromCtx[T any](ctx context.Context, key any) Optional[T] {
+	stored := ctx.Value(key)
+	if stored == nil {
+		return None[T]()
+	}
+	if o, ok := stored.(Optional[T]); ok {
+		return o
+	}
+	return codeErr[T](CONVERSION_ERROR_CODE, fmt.Errorf("FromCtx: context value for %v is %T, not %T", key, stored, Optional[T]{}))
+}
+
+// Discard a value that arrived too late: if the deadline of ctx has passed, a value is replaced
+// by an error with DEADLINE_ERROR_CODE wrapping context.DeadlineExceeded.
+// Errors, empty Optionals and contexts that are canceled without a passed deadline are returned unchanged.
+func (o Optional[T]) WithDeadline(ctx context.Context) Optional[T] {
+	if o.IsError() || !o.present {
+		return o
+	}
+	deadline, ok := ctx.Deadline()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || (ok && !time.Now().Before(deadline)) {
+		return codeErr[T](DEADLINE_ERROR_CODE, fmt.Errorf("WithDeadline: value arrived after deadline %v: %w", deadline, context.DeadlineExceeded))
+	}
+	return o
+}
+
+// Convert to a protobuf-style wrapper (e.g. *wrapperspb.Int64Value), nil on error or if empty.
+// wrap builds the wrapper from a value, e.g. func(v int64) wrapperspb.Int64Value { ... }.
+func ToWrapper[T any, W any](o Optional[T], wrap func(T) W) *W {
+	if o.IsError() || !o.present {
+		return nil
+	}
+	w := wrap(o.Value)
+	return &w
+}
+
+// Convert from a protobuf-style wrapper, a nil wrapper results in an empty Optional.
+// unwrap extracts the value, e.g. the method expression (*wrapperspb.Int64Value).GetValue.
+func FromWrapper[T any, W any](w *W, unwrap func(*W) T) Optional[T] {
+	if w == nil {
+		return None[T]()
+	}
+	return Ok(unwrap(w))
+}
+
+// Convert to the (value, present) pair of a proto3 optional field, e.g. for x.SetCount(v) if present.
+// A present zero value is present, errors and empty Optionals give (zero value, false).
+func ToProtoOptional[T any](o Optional[T]) (value T, present bool) {
+	if o.IsError() || !o.present {
+		return value, false
+	}
+	return o.Value, true
+}
+
+// Convert from the (value, present) pair of a proto3 optional field, e.g. FromProtoOptional(x.GetCount(), x.HasCount()).
+// Not present results in an empty Optional, a present zero value stays present.
+func FromProtoOptional[T any](value T, present bool) Optional[T] {
+	return Make(value, present, nil, 0)
+}
+
+//*********************************************************************************
+//                                Function Adapters
+//*********************************************************************************
+
+// Adapt a traditional Go function to return an Optional, see GoOpt.
+func WrapFunc[A any, B any](f func(A) (B, error)) func(A) Optional[B] {
+	return func(a A) Optional[B] { return GoOpt(f(a)) }
+}
+
+// Adapt a function returning an Optional to the traditional Go (value, error) form, see ToGo.
+func UnwrapFunc[A any, B any](f func(A) Optional[B]) func(A) (B, error) {
+	return func(a A) (B, error) { return f(a).ToGo() }
+}
+
+// Two argument variant of WrapFunc.
+func WrapFunc2[A1 any, A2 any, B any](f func(A1, A2) (B, error)) func(A1, A2) Optional[B] {
+	return func(a1 A1, a2 A2) Optional[B] { return GoOpt(f(a1, a2)) }
+}
+
+// Two argument variant of UnwrapFunc.
+func UnwrapFunc2[A1 any, A2 any, B any](f func(A1, A2) Optional[B]) func(A1, A2) (B, error) {
+	return func(a1 A1, a2 A2) (B, error) { return f(a1, a2).ToGo() }
+}
@@ -0,0 +1,136 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stands in for wrapperspb.Int64Value
+type int64Wrapper struct{ Value int64 }
+
+func (w *int64Wrapper) GetValue() int64 {
+	if w == nil { return 0 }
+	return w.Value
+}
+
+func TestInterop(t *testing.T) {
+	t.Run("FlagVar parses valid input", func(t *testing.T) {
+		var port Optional[int]
+		value := FlagVar(&port, strconv.Atoi)
+		if value.String() != "" { t.Fatalf("expected empty string for unset flag, got %q", value.String()) }
+		if err := value.Set("8080"); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if port.IsError() || port.Value != 8080 { t.Fatalf("expected 8080, got %v", port) }
+		if value.String() != "8080" { t.Fatalf("expected \"8080\", got %q", value.String()) }
+	})
+
+	t.Run("FlagVar stores parse failure", func(t *testing.T) {
+		var port Optional[int]
+		value := FlagVar(&port, strconv.Atoi)
+		if err := value.Set("http"); err == nil { t.Fatalf("expected parse error") }
+		if !port.IsError() { t.Fatalf("expected error Optional") }
+		if value.String() != "" { t.Fatalf("expected empty string on error, got %q", value.String()) }
+	})
+
+	t.Run("FlagVar works with FlagSet", func(t *testing.T) {
+		var port, retries Optional[int]
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(FlagVar(&port, strconv.Atoi), "port", "port to listen on")
+		fs.Var(FlagVar(&retries, strconv.Atoi), "retries", "retry count")
+		if err := fs.Parse([]string{"-port", "9000"}); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if port.Value != 9000 { t.Fatalf("expected 9000, got %v", port) }
+		if !retries.IsNone() { t.Fatalf("unset flag must stay none") }
+		fs.PrintDefaults()
+	})
+
+	t.Run("ToWrapper", func(t *testing.T) {
+		wrap := func(v int64) int64Wrapper { return int64Wrapper{Value: v} }
+		if w := ToWrapper(Ok(int64(0)), wrap); w == nil || w.Value != 0 { t.Fatalf("expected wrapper for present zero value, got %v", w) }
+		if w := ToWrapper(Ok(int64(12)), wrap); w == nil || w.Value != 12 { t.Fatalf("expected wrapper with 12, got %v", w) }
+		if w := ToWrapper(None[int64](), wrap); w != nil { t.Fatalf("expected nil for none, got %v", w) }
+		if w := ToWrapper(Err[int64]("e"), wrap); w != nil { t.Fatalf("expected nil for error, got %v", w) }
+	})
+
+	t.Run("FromWrapper", func(t *testing.T) {
+		if o := FromWrapper(&int64Wrapper{Value: 5}, (*int64Wrapper).GetValue); o.IsError() || o.Value != 5 { t.Fatalf("expected 5, got %v", o) }
+		if o := FromWrapper(&int64Wrapper{}, (*int64Wrapper).GetValue); o.IsNone() { t.Fatalf("wrapped zero value must be present") }
+		if o := FromWrapper[int64]((*int64Wrapper)(nil), (*int64Wrapper).GetValue); !o.IsNone() { t.Fatalf("expected none for nil wrapper, got %v", o) }
+	})
+
+	t.Run("WrapFunc and UnwrapFunc", func(t *testing.T) {
+		atoi := WrapFunc(strconv.Atoi)
+		if o := atoi("12"); o.IsError() || o.Value != 12 { t.Fatalf("expected 12, got %v", o) }
+		if o := atoi("x"); !o.IsError() { t.Fatalf("expected error") }
+
+		back := UnwrapFunc(atoi)
+		if v, err := back("7"); err != nil || v != 7 { t.Fatalf("expected 7, got %v %v", v, err) }
+		if _, err := back("x"); err == nil { t.Fatalf("expected error") }
+	})
+
+	t.Run("WrapFunc2 and UnwrapFunc2", func(t *testing.T) {
+		parse := WrapFunc2(func(s string, base int) (int64, error) { return strconv.ParseInt(s, base, 64) })
+		if o := parse("ff", 16); o.IsError() || o.Value != 255 { t.Fatalf("expected 255, got %v", o) }
+
+		back := UnwrapFunc2(parse)
+		if _, err := back("zz", 10); err == nil { t.Fatalf("expected error") }
+	})
+
+	t.Run("FromEnv", func(t *testing.T) {
+		t.Setenv("OPTIONAL_TEST_SET", "value")
+		t.Setenv("OPTIONAL_TEST_EMPTY", "")
+		if o := FromEnv("OPTIONAL_TEST_SET"); o.Value != "value" || o.IsNone() { t.Fatalf("expected value, got %v", o) }
+		if o := FromEnv("OPTIONAL_TEST_EMPTY"); o.IsNone() || o.Value != "" { t.Fatalf("empty but set must be present, got %v", o) }
+		if o := FromEnv("OPTIONAL_TEST_UNSET"); !o.IsNone() { t.Fatalf("expected none for unset, got %v", o) }
+	})
+
+	t.Run("FromEnvAs", func(t *testing.T) {
+		t.Setenv("OPTIONAL_TEST_PORT", "8080")
+		t.Setenv("OPTIONAL_TEST_BAD", "")
+		if o := FromEnvAs("OPTIONAL_TEST_PORT", strconv.Atoi); o.IsError() || o.Value != 8080 { t.Fatalf("expected 8080, got %v", o) }
+		if o := FromEnvAs("OPTIONAL_TEST_BAD", strconv.Atoi); !o.IsError() || !strings.Contains(o.Error.Error(), "OPTIONAL_TEST_BAD") { t.Fatalf("expected parse error naming the variable, got %v", o) }
+		if o := FromEnvAs("OPTIONAL_TEST_UNSET", strconv.Atoi); !o.IsNone() { t.Fatalf("expected none for unset, got %v", o) }
+	})
+
+	t.Run("ContextWith and FromCtx", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := ContextWith(context.Background(), ctxKey{}, Ok("alice"))
+		if o := FromCtx[string](ctx, ctxKey{}); o.Value != "alice" { t.Fatalf("expected alice, got %v", o) }
+		if o := FromCtx[string](context.Background(), ctxKey{}); !o.IsNone() { t.Fatalf("expected none for missing key, got %v", o) }
+		if o := FromCtx[int](ctx, ctxKey{}); o.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected type mismatch error, got %v", o) }
+		plain := context.WithValue(context.Background(), ctxKey{}, "raw")
+		if o := FromCtx[string](plain, ctxKey{}); o.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected error for raw value, got %v", o) }
+		errCtx := ContextWith(context.Background(), ctxKey{}, CodeErr[string](3, "unauthenticated"))
+		if o := FromCtx[string](errCtx, ctxKey{}); o.ErrorCode != 3 { t.Fatalf("expected stored error, got %v", o) }
+	})
+
+	t.Run("WithDeadline discards late values", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		if o := Ok(1).WithDeadline(ctx); o != Ok(1) { t.Fatalf("expected value before deadline, got %v", o) }
+		if o := Ok(1).WithDeadline(context.Background()); o != Ok(1) { t.Fatalf("expected value without deadline, got %v", o) }
+		expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancelExpired()
+		o := Ok(1).WithDeadline(expired)
+		if o.ErrorCode != DEADLINE_ERROR_CODE || !errors.Is(o.Error, context.DeadlineExceeded) { t.Fatalf("expected deadline error, got %v (%d)", o, o.ErrorCode) }
+		if o := CodeErr[int](4, "e").WithDeadline(expired); o.ErrorCode != 4 { t.Fatalf("expected error unchanged, got %v", o) }
+		if o := None[int]().WithDeadline(expired); !o.IsNone() { t.Fatalf("expected none unchanged, got %v", o) }
+		canceled, cancelNow := context.WithCancel(context.Background())
+		cancelNow()
+		if o := Ok(1).WithDeadline(canceled); o != Ok(1) { t.Fatalf("expected value for canceled context without deadline, got %v", o) }
+	})
+
+	t.Run("ToProtoOptional and FromProtoOptional", func(t *testing.T) {
+		if v, present := ToProtoOptional(Ok(int64(0))); !present || v != 0 { t.Fatalf("expected present zero value, got %v %v", v, present) }
+		if v, present := ToProtoOptional(Ok("x")); !present || v != "x" { t.Fatalf("expected present x, got %v %v", v, present) }
+		if _, present := ToProtoOptional(None[int64]()); present { t.Fatalf("expected none to be absent") }
+		if v, present := ToProtoOptional(GoOpt(int64(3), io.EOF)); present || v != 0 { t.Fatalf("expected error to be absent without best-effort value, got %v %v", v, present) }
+		if o := FromProtoOptional(int64(0), true); !o.IsSomeStrict() || o.Value != 0 { t.Fatalf("expected present zero value, got %v", o) }
+		if o := FromProtoOptional(int64(0), false); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+	})
+}
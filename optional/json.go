@@ -0,0 +1,124 @@
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+//*********************************************************************************
+//                                 JSON Encoding
+//*********************************************************************************
+
+// Envelope used to encode an Optional as JSON:
+//
+//	null                          empty Optional
+//	{"value": 42}                 value
+//	{"error": "boom", "code": 7}  error (code omitted if 0)
+//	{"value": 42, "error": "..."} error with value, see GoOpt
+type jsonEnvelope struct {
+	Value json.RawMessage `json:"value,omitempty"`
+	Error *string         `json:"error,omitempty"`
+	Code  uint32          `json:"code,omitempty"`
+}
+
+// Encode the Optional as JSON envelope, see jsonEnvelope.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if o.IsNone() {
+		return []byte("null"), nil
+	}
+	var env jsonEnvelope
+	if o.present {
+		value, err := json.Marshal(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		env.Value = value
+	}
+	if o.Error != nil {
+		msg := o.Error.Error()
+		env.Error = &msg
+	}
+	env.Code = o.ErrorCode
+	return json.Marshal(env)
+}
+
+// Decode the Optional from a JSON envelope, see jsonEnvelope.
+// The error is restored from its message as is, without calling the error handlers again.
+// Like encoding/json for plain fields, a value is decoded into the existing Value, so an interface
+// holding a pointer (e.g. Value = &target for Optional[any]) is decoded into the pointed-to target.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var env *jsonEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	decoded := Optional[T]{}
+	if env != nil {
+		if env.Value != nil {
+			decoded.Value = o.Value
+			if err := json.Unmarshal(env.Value, &decoded.Value); err != nil {
+				return err
+			}
+			decoded.present = true
+		}
+		if env.Error != nil {
+			decoded.Error = errors.New(*env.Error)
+		}
+		decoded.ErrorCode = env.Code
+	}
+	*o = decoded
+	return nil
+}
+
+// Encode a batch of Optionals as JSON array of envelopes, keeping the state of every element.
+func MarshalSlice[T any](opts []Optional[T]) ([]byte, error) {
+	return json.Marshal(opts)
+}
+
+// Decode a batch of Optionals encoded by MarshalSlice.
+func UnmarshalSlice[T any](data []byte) ([]Optional[T], error) {
+	var opts []Optional[T]
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// Decode untrusted JSON holding a plain T (not an envelope), e.g. a request body at an API boundary.
+// Returns an empty Optional for null and an error with DECODE_ERROR_CODE for malformed input or a type mismatch.
+// Never panics: a panic in a custom UnmarshalJSON becomes an error with RECOVERED_PANIC_CODE.
+func DecodeJSON[T any](data []byte) (result Optional[T]) {
+	defer Guard(&result)
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return None[T]()
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return codeErr[T](DECODE_ERROR_CODE, fmt.Errorf("DecodeJSON[%T]: %w", v, err))
+	}
+	return Ok(v)
+}
+
+// Write the Optionals received from opts to w as newline-delimited JSON envelopes until opts is closed,
+// e.g. for a streaming HTTP response. If w has a Flush method (http.Flusher, bufio.Writer), it is
+// flushed after every element. Writes block on w, so a slow reader also slows down the sender on opts.
+// Returns the first encoding, write or flush error without draining opts.
+func EncodeStream[T any](w io.Writer, opts <-chan Optional[T]) error {
+	enc := json.NewEncoder(w)
+	for o := range opts {
+		if err := enc.Encode(o); err != nil {
+			return err
+		}
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			if err := f.Flush(); err != nil {
+				return err
+			}
+		case interface{ Flush() }:
+			f.Flush()
+		}
+	}
+	return nil
+}
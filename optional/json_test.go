@@ -0,0 +1,140 @@
+package optional
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("Envelope format", func(t *testing.T) {
+		cases := []struct {
+			opt  Optional[int]
+			want string
+		}{
+			{Ok(42), `{"value":42}`},
+			{Ok(0), `{"value":0}`},
+			{None[int](), `null`},
+			{CodeErr[int](7, "boom"), `{"error":"boom","code":7}`},
+			{Err[int]("boom"), `{"error":"boom"}`},
+		}
+		for _, c := range cases {
+			data, err := json.Marshal(c.opt)
+			if err != nil { t.Fatalf("unexpected error: %v", err) }
+			if string(data) != c.want { t.Fatalf("expected %s, got %s", c.want, data) }
+		}
+	})
+
+	t.Run("MarshalSlice round trip", func(t *testing.T) {
+		in := []Optional[string]{Ok("a"), Ok(""), CodeErr[string](123, "boom"), None[string](), GoOpt("partial", Err[string]("e").Error)}
+		data, err := MarshalSlice(in)
+		if err != nil { t.Fatalf("unexpected error: %v", err) }
+		out, err := UnmarshalSlice[string](data)
+		if err != nil { t.Fatalf("unexpected error: %v", err) }
+		if len(out) != len(in) { t.Fatalf("expected %d elements, got %d", len(in), len(out)) }
+		for i := range in {
+			if in[i].Comparable() != out[i].Comparable() { t.Fatalf("[%d] expected %+v, got %+v", i, in[i].Comparable(), out[i].Comparable()) }
+		}
+		if out[2].ErrorCode != 123 { t.Fatalf("expected code 123 preserved, got %d", out[2].ErrorCode) }
+		if !out[3].IsNone() || out[1].IsNone() { t.Fatalf("none and present zero value must be distinguished") }
+	})
+
+	t.Run("UnmarshalSlice rejects malformed input", func(t *testing.T) {
+		if _, err := UnmarshalSlice[int]([]byte(`[{"value":"x"}]`)); err == nil { t.Fatalf("expected type error") }
+		if _, err := UnmarshalSlice[int]([]byte(`[`)); err == nil { t.Fatalf("expected syntax error") }
+	})
+
+	t.Run("Round trip of tricky types", func(t *testing.T) {
+		n := 0
+		type inner struct{ ID int }
+		type outer struct {
+			inner
+			Name  string
+			Child Optional[string]
+		}
+		roundTrip(t, "pointer", Ok(&n), func(o Optional[*int]) bool { return o.IsSomeStrict() && o.Value != nil && *o.Value == 0 })
+		roundTrip(t, "nil pointer", Ok[*int](nil), func(o Optional[*int]) bool { return o.IsSomeStrict() && o.Value == nil })
+		roundTrip(t, "none pointer", None[*int](), func(o Optional[*int]) bool { return o.IsNone() })
+		roundTrip(t, "nil slice", Ok[[]int](nil), func(o Optional[[]int]) bool { return o.IsSomeStrict() && o.Value == nil })
+		roundTrip(t, "slice", Ok([]int{1, 2}), func(o Optional[[]int]) bool { return len(o.Value) == 2 && o.Value[1] == 2 })
+		roundTrip(t, "map", Ok(map[string]int{"a": 1}), func(o Optional[map[string]int]) bool { return o.Value["a"] == 1 })
+		roundTrip(t, "embedded struct", Ok(outer{inner: inner{ID: 3}, Name: "n", Child: None[string]()}), func(o Optional[outer]) bool {
+			return o.Value.ID == 3 && o.Value.Name == "n" && o.Value.Child.IsNone()
+		})
+		roundTrip(t, "nested none", Ok(None[int]()), func(o Optional[Optional[int]]) bool { return o.IsSomeStrict() && o.Value.IsNone() })
+		roundTrip(t, "nested error", Ok(CodeErr[int](3, "inner")), func(o Optional[Optional[int]]) bool { return o.IsSomeStrict() && o.Value.ErrorCode == 3 })
+		roundTrip(t, "nested zero", Ok(Ok(0)), func(o Optional[Optional[int]]) bool { return o.Value.IsSomeStrict() })
+		roundTrip(t, "outer none", None[Optional[int]](), func(o Optional[Optional[int]]) bool { return o.IsNone() })
+		roundTrip(t, "interface nil", Ok[any](nil), func(o Optional[any]) bool { return o.IsSomeStrict() && o.Value == nil })
+		roundTrip(t, "interface value", Ok[any]("s"), func(o Optional[any]) bool { return o.Value == "s" })
+		roundTrip(t, "interface none", None[any](), func(o Optional[any]) bool { return o.IsNone() })
+		roundTrip(t, "error with nil pointer", GoOpt[*int](nil, Err[int]("e").Error), func(o Optional[*int]) bool { return o.IsError() && o.present })
+	})
+
+	t.Run("Unmarshal into interface holding a pointer", func(t *testing.T) {
+		type user struct{ Name string }
+		var target user
+		opt := Optional[any]{Value: &target}
+		if err := json.Unmarshal([]byte(`{"value":{"Name":"ada"}}`), &opt); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if target.Name != "ada" { t.Fatalf("expected decoding into target, got %+v", target) }
+		if opt.Value != &target || !opt.IsSomeStrict() { t.Fatalf("expected present value pointing to target, got %v", opt.Comparable()) }
+	})
+
+	t.Run("Unmarshal resets previous state", func(t *testing.T) {
+		opt := CodeErr[int](5, "old")
+		if err := json.Unmarshal([]byte(`{"value":1}`), &opt); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if opt.IsError() || opt.Value != 1 { t.Fatalf("expected clean value, got %v", opt.Comparable()) }
+		if err := json.Unmarshal([]byte(`null`), &opt); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if !opt.IsNone() { t.Fatalf("expected none after null, got %v", opt.Comparable()) }
+	})
+
+	t.Run("DecodeJSON", func(t *testing.T) {
+		type user struct{ Name string `json:"name"` }
+		if o := DecodeJSON[user]([]byte(`{"name":"ada"}`)); !o.IsSomeStrict() || o.Value.Name != "ada" { t.Fatalf("expected ada, got %v", o) }
+		if o := DecodeJSON[int]([]byte(`0`)); !o.IsSomeStrict() || o.Value != 0 { t.Fatalf("expected present zero value, got %v", o) }
+		if o := DecodeJSON[user]([]byte(" null ")); !o.IsNone() { t.Fatalf("expected none for null, got %v", o) }
+		for _, input := range []string{`{"name":`, ``, `{"name":1}`, `[1,2]`} {
+			if o := DecodeJSON[user]([]byte(input)); o.ErrorCode != DECODE_ERROR_CODE { t.Fatalf("expected DECODE_ERROR_CODE for %q, got %v (%d)", input, o, o.ErrorCode) }
+		}
+		if o := DecodeJSON[panickyJSON]([]byte(`{}`)); o.ErrorCode != RECOVERED_PANIC_CODE { t.Fatalf("expected recovered panic, got %v (%d)", o, o.ErrorCode) }
+	})
+
+	t.Run("EncodeStream writes one envelope per line", func(t *testing.T) {
+		opts := make(chan Optional[int], 3)
+		opts <- Ok(1)
+		opts <- CodeErr[int](4, "e")
+		opts <- None[int]()
+		close(opts)
+		rec := httptest.NewRecorder()
+		if err := EncodeStream(rec, opts); err != nil { t.Fatalf("unexpected error %v", err) }
+		if got := rec.Body.String(); got != "{\"value\":1}\n{\"error\":\"e\",\"code\":4}\nnull\n" { t.Fatalf("unexpected stream %q", got) }
+		if !rec.Flushed { t.Fatalf("expected writer to be flushed") }
+	})
+
+	t.Run("EncodeStream stops at the first write error", func(t *testing.T) {
+		opts := make(chan Optional[int], 2)
+		opts <- Ok(1)
+		opts <- Ok(2)
+		if err := EncodeStream(failingWriter{}, opts); !errors.Is(err, io.ErrClosedPipe) { t.Fatalf("expected write error, got %v", err) }
+		if len(opts) != 1 { t.Fatalf("expected remaining element to stay in the channel, got %d", len(opts)) }
+	})
+}
+
+func roundTrip[T any](t *testing.T, name string, in Optional[T], check func(Optional[T]) bool) {
+	t.Helper()
+	data, err := json.Marshal(in)
+	if err != nil { t.Fatalf("%s: marshal failed: %v", name, err) }
+	var out Optional[T]
+	if err := json.Unmarshal(data, &out); err != nil { t.Fatalf("%s: unmarshal of %s failed: %v", name, data, err) }
+	if !check(out) { t.Fatalf("%s: unexpected result %+v from %s", name, out.Comparable(), data) }
+}
+
+type panickyJSON struct{}
+
+func (*panickyJSON) UnmarshalJSON([]byte) error { panic("broken decoder") }
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
@@ -0,0 +1,130 @@
+package optional
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+//*********************************************************************************
+//                                  struct Lazy
+//*********************************************************************************
+
+// Defers a computation until its result is first requested.
+// The result is computed once and memoized, including an error. Safe for concurrent use.
+type Lazy[T any] struct {
+	once    sync.Once
+	produce func() Optional[T]
+	result  Optional[T]
+}
+
+// Create a Lazy that computes its result with produce on the first call of Get.
+func NewLazy[T any](produce func() Optional[T]) *Lazy[T] {
+	return &Lazy[T]{produce: produce}
+}
+
+// Get the result, computing it on the first call.
+// Concurrent first calls wait for a single run of the producer.
+// If the producer panics, the panic is propagated to the first caller and every later Get
+// returns an error with RECOVERED_PANIC_CODE, the producer is not run again.
+func (l *Lazy[T]) Get() Optional[T] {
+	l.once.Do(func() {
+		completed := false
+		defer func() {
+			if !completed {
+				l.result = Optional[T]{Error: errors.New("Lazy: producer panicked"), ErrorCode: RECOVERED_PANIC_CODE}
+			}
+			l.produce = nil // release captured state
+		}()
+		l.result = l.produce()
+		completed = true
+	})
+	return l.result
+}
+
+//*********************************************************************************
+//                               struct OnceOptional
+//*********************************************************************************
+
+// Like sync.Once, but result-carrying and retrying on error:
+// a successful result is cached forever, an error result is returned but not cached,
+// so the next call of Do runs its function again. The zero value is ready to use.
+type OnceOptional[T any] struct {
+	mu     sync.Mutex
+	done   atomic.Bool
+	result Optional[T]
+}
+
+// Return the cached successful result, or run f and cache its result if it is not an error.
+// Calls are serialized, concurrent callers wait for a running f and do not run f themselves if it succeeded.
+func (once *OnceOptional[T]) Do(f func() Optional[T]) Optional[T] {
+	if once.done.Load() {
+		return once.result
+	}
+	once.mu.Lock()
+	defer once.mu.Unlock()
+	if once.done.Load() {
+		return once.result
+	}
+	res := f()
+	if !res.IsError() {
+		once.result = res
+		once.done.Store(true)
+	}
+	return res
+}
+
+//*********************************************************************************
+//                                  struct Memo
+//*********************************************************************************
+
+// Caches an Optional per key, computing each key at most once even under concurrent access:
+// concurrent callers for a key that is being computed wait for that computation and share its result.
+// The zero value is ready to use and must not be copied after first use.
+type Memo[K comparable, V any] struct {
+	// Cache error results as well. If false, an error is returned to the callers waiting for it,
+	// but the next Get for the key computes again, so transient failures can be retried.
+	CacheErrors bool
+
+	mu      sync.Mutex
+	entries map[K]*memoEntry[V]
+}
+
+type memoEntry[V any] struct {
+	done   chan struct{}
+	result Optional[V]
+}
+
+// Get the cached result for k, or compute it with compute.
+// If compute panics, the panic is propagated to the caller, waiting callers receive an error
+// with RECOVERED_PANIC_CODE and the key is computed again by the next Get.
+func (m *Memo[K, V]) Get(k K, compute func(K) Optional[V]) Optional[V] {
+	m.mu.Lock()
+	if e, ok := m.entries[k]; ok {
+		m.mu.Unlock()
+		<-e.done
+		return e.result
+	}
+	if m.entries == nil {
+		m.entries = map[K]*memoEntry[V]{}
+	}
+	e := &memoEntry[V]{done: make(chan struct{})}
+	m.entries[k] = e
+	m.mu.Unlock()
+
+	completed := false
+	defer func() {
+		if !completed {
+			e.result = Optional[V]{Error: errors.New("Memo: computation panicked"), ErrorCode: RECOVERED_PANIC_CODE}
+		}
+		if !completed || (e.result.IsError() && !m.CacheErrors) {
+			m.mu.Lock()
+			delete(m.entries, k)
+			m.mu.Unlock()
+		}
+		close(e.done)
+	}()
+	e.result = compute(k)
+	completed = true
+	return e.result
+}
@@ -0,0 +1,118 @@
+package optional
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("Lazy computes on first Get", func(t *testing.T) {
+		calls := 0
+		l := NewLazy(func() Optional[int] { calls++; return Ok(3) })
+		if calls != 0 { t.Fatalf("producer must not run before Get") }
+		if v := l.Get(); v.Value != 3 { t.Fatalf("expected 3, got %v", v) }
+		if v := l.Get(); v.Value != 3 || calls != 1 { t.Fatalf("expected memoized 3 with one call, got %v after %d calls", v, calls) }
+	})
+
+	t.Run("Lazy memoizes errors", func(t *testing.T) {
+		calls := 0
+		l := NewLazy(func() Optional[string] { calls++; return CodeErr[string](9, "unavailable") })
+		l.Get()
+		if v := l.Get(); v.ErrorCode != 9 || calls != 1 { t.Fatalf("expected memoized error with one call, got %v after %d calls", v, calls) }
+	})
+
+	t.Run("Lazy reports a panicking producer as error", func(t *testing.T) {
+		calls := 0
+		l := NewLazy(func() Optional[int] { calls++; panic("broken producer") })
+		if r := mustPanic(t, func() { l.Get() }); r != "broken producer" { t.Fatalf("expected producer panic, got %v", r) }
+		if v := l.Get(); v.ErrorCode != RECOVERED_PANIC_CODE || v.Error == nil || calls != 1 { t.Fatalf("expected RECOVERED_PANIC_CODE without rerun, got %v (%d) after %d calls", v, v.ErrorCode, calls) }
+	})
+
+	t.Run("Lazy runs producer once under concurrency", func(t *testing.T) {
+		var calls atomic.Int32
+		l := NewLazy(func() Optional[int] { calls.Add(1); return Ok(1) })
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() { defer wg.Done(); l.Get() }()
+		}
+		wg.Wait()
+		if calls.Load() != 1 { t.Fatalf("expected one producer call, got %d", calls.Load()) }
+	})
+
+	t.Run("OnceOptional caches success", func(t *testing.T) {
+		var once OnceOptional[int]
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v := once.Do(func() Optional[int] { calls.Add(1); return Ok(7) }); v.Value != 7 { t.Errorf("expected 7, got %v", v) }
+			}()
+		}
+		wg.Wait()
+		if calls.Load() != 1 { t.Fatalf("expected one call, got %d", calls.Load()) }
+	})
+
+	t.Run("OnceOptional retries after error", func(t *testing.T) {
+		var once OnceOptional[int]
+		var calls atomic.Int32
+		f := func() Optional[int] {
+			if calls.Add(1) < 3 { return Err[int]("transient") }
+			return Ok(1)
+		}
+		if v := once.Do(f); !v.IsError() { t.Fatalf("expected first error") }
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() { defer wg.Done(); once.Do(f) }()
+		}
+		wg.Wait()
+		if calls.Load() != 3 { t.Fatalf("expected retries to stop after success at call 3, got %d calls", calls.Load()) }
+		if v := once.Do(f); v.IsError() || v.Value != 1 { t.Fatalf("expected cached success, got %v", v) }
+	})
+
+	t.Run("Memo computes each key once under concurrency", func(t *testing.T) {
+		var m Memo[string, int]
+		var calls atomic.Int32
+		release := make(chan struct{})
+		compute := func(k string) Optional[int] { calls.Add(1); <-release; return Ok(len(k)) }
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if o := m.Get("tenant", compute); o.Value != 6 { t.Errorf("expected 6, got %v", o) }
+			}()
+		}
+		close(release)
+		wg.Wait()
+		if calls.Load() != 1 { t.Fatalf("expected one computation, got %d", calls.Load()) }
+		if o := m.Get("other", compute); o.Value != 5 || calls.Load() != 2 { t.Fatalf("expected separate computation per key") }
+	})
+
+	t.Run("Memo retries errors unless CacheErrors", func(t *testing.T) {
+		calls := 0
+		failing := func(string) Optional[int] { calls++; return Err[int]("unavailable") }
+		var retrying Memo[string, int]
+		retrying.Get("k", failing)
+		retrying.Get("k", failing)
+		if calls != 2 { t.Fatalf("expected errors to be recomputed, got %d calls", calls) }
+
+		calls = 0
+		caching := Memo[string, int]{CacheErrors: true}
+		caching.Get("k", failing)
+		if o := caching.Get("k", failing); !o.IsError() || calls != 1 { t.Fatalf("expected cached error after one call, got %d calls", calls) }
+	})
+
+	t.Run("Memo recovers from panicking computation", func(t *testing.T) {
+		var m Memo[int, int]
+		func() {
+			defer func() { recover() }()
+			m.Get(1, func(int) Optional[int] { panic("boom") })
+		}()
+		if o := m.Get(1, func(int) Optional[int] { return Ok(1) }); o.Value != 1 { t.Fatalf("expected recomputation after panic, got %v", o) }
+	})
+}
@@ -1,16 +1,83 @@
 package optional
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"reflect"
+	"slices"
+	"sync"
 )
 
 const PANIC_CODE = math.MaxUint32
 
+// Codes from RESERVED_CODE_MIN up to PANIC_CODE are reserved for this package:
+// PANIC_CODE escalates to a panic, the codes below it are assigned by the package itself.
+// Application codes must lie in [1, RESERVED_CODE_MIN), SetStrictCodes(true) enforces this in CodeErr.
+const RESERVED_CODE_MIN = PANIC_CODE - 0xFF
+
+// Error codes assigned by this package, reserved directly below PANIC_CODE.
+const (
+	// Cast failed because the types are not compatible (only if SetCastPanics(false)).
+	CAST_ERROR_CODE = PANIC_CODE - 1 - iota
+	// A helper was called with an invalid argument, e.g. a chunk size <= 0.
+	ARGUMENT_ERROR_CODE
+	// A value could not be converted to the target type, e.g. a numeric overflow.
+	CONVERSION_ERROR_CODE
+	// A panic was recovered and converted to an error.
+	RECOVERED_PANIC_CODE
+	// Two elements mapped to the same key, e.g. in ToMap.
+	DUPLICATE_KEY_ERROR_CODE
+	// A key was not found, e.g. in MapLookupThen.
+	NOT_FOUND_ERROR_CODE
+	// A value arrived after the deadline of its context, see WithDeadline.
+	DEADLINE_ERROR_CODE
+	// Input could not be decoded, e.g. malformed JSON in DecodeJSON.
+	DECODE_ERROR_CODE
+)
+
 type Void struct{} // sentinel stating nothing is returned by a function. Optional[Void] infers that only error state can be returned.
 
+// Two values carried by a single Optional, e.g. for functions returning (A, B, error).
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Integer and floating point types supported by ConvertNumeric.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Implemented by error types that carry their own error code.
+// CodeErr and Err use Code() if they are called with code 0.
+type CodedError interface {
+	error
+	Code() uint32
+}
+
+// The error of an Optional together with its code, returned by AsError.
+// Implements CodedError, so CodeErr picks the code up again.
+type OptionalError struct {
+	Err       error
+	ErrorCode uint32
+}
+
+func (e *OptionalError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("error code %d", e.ErrorCode)
+	}
+	return e.Err.Error()
+}
+
+func (e *OptionalError) Unwrap() error { return e.Err }
+func (e *OptionalError) Code() uint32  { return e.ErrorCode }
+
 //*********************************************************************************
 //                               struct Optional
 //*********************************************************************************
@@ -23,16 +90,92 @@ type Optional[T any] struct {
 	Error error
 	// Contains the error code if the operation failed, 0 otherwise.
 	ErrorCode uint32
+	// Set if Value was supplied by the constructor, even if it is the zero value.
+	present bool
 }
 
-// Returns if the Optional contains a value regardless of whether or not it contains an error.
+// Returns if the Optional contains a non-zero value and no error.
+// Zero sized types like Void have no non-zero value, for those the presence of the value is reported instead.
+// Beware that a legitimate zero value reports false, use IsSomeStrict to check for any value.
 func (o Optional[T]) IsSome() bool {
-	return !reflect.ValueOf(o.Value).IsZero()
+	if o.IsError() {
+		return false
+	}
+	value := reflect.ValueOf(&o.Value).Elem()
+	if value.Type().Size() == 0 {
+		return o.present
+	}
+	return !value.IsZero()
+}
+
+// Returns if the Optional contains a value and no error, based only on whether a value was supplied.
+// Unlike IsSome, a present zero value (e.g. Ok(0)) reports true. Exactly one of IsSomeStrict, IsNone
+// and IsError is true for every Optional. IsSome keeps its zero value check for compatibility.
+func (o Optional[T]) IsSomeStrict() bool {
+	return o.present && !o.IsError()
 }
 
 // Get the contained value, asserting that it exists.
+// On error, the observer set by SetUnwrapObserver is called before panicking.
 func (o Optional[T]) Unwrap() T {
 	if o.IsError() {
+		notifyUnwrapObserver(o.Error, o.ErrorCode)
+		panic(o.Error)
+	}
+	return o.Value
+}
+
+// Like Unwrap, but panics with an error that also names the code:
+//
+//	optional failed [code 404]: user not found
+//
+// The original error stays reachable through errors.Is / errors.As on the recovered value.
+func (o Optional[T]) UnwrapOrPanic() T {
+	if o.IsError() {
+		notifyUnwrapObserver(o.Error, o.ErrorCode)
+		if o.Error == nil {
+			panic(fmt.Errorf("optional failed [code %d]", o.ErrorCode))
+		}
+		panic(fmt.Errorf("optional failed [code %d]: %w", o.ErrorCode, o.Error))
+	}
+	return o.Value
+}
+
+// Get the contained value, or the zero value of T on error. Never panics.
+// Unlike Value, this does not return the best-effort value GoOpt keeps on error.
+func (o Optional[T]) UnwrapOrZero() T {
+	if o.IsError() {
+		var zero T
+		return zero
+	}
+	return o.Value
+}
+
+// Get the contained value regardless of the error state, never panics.
+// Dangerous: on error this is only the best-effort value kept by GoOpt, which may be partial or the zero value.
+// Only use it if you deliberately want that value, otherwise use Unwrap or UnwrapOrZero.
+func (o Optional[T]) UnwrapIgnoringError() T {
+	return o.Value
+}
+
+// Get the contained value, or the default computed by f from ctx on error or if empty,
+// e.g. a tenant or locale specific default. f is not called if a value is present, even a zero value.
+func (o Optional[T]) UnwrapOrCtx(ctx context.Context, f func(context.Context) T) T {
+	if o.IsSomeStrict() {
+		return o.Value
+	}
+	return f(ctx)
+}
+
+// Like Unwrap, but logs the error and code at error level before panicking.
+// A nil logger logs to slog.Default().
+func (o Optional[T]) UnwrapLog(logger *slog.Logger) T {
+	if o.IsError() {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Error("unwrap of error Optional", "error", o.Error, "code", o.ErrorCode)
+		notifyUnwrapObserver(o.Error, o.ErrorCode)
 		panic(o.Error)
 	}
 	return o.Value
@@ -46,6 +189,160 @@ func (o Optional[T]) HasErrorCode() bool {
 	return o.ErrorCode != 0
 }
 
+// Returns if the Optional is empty, it contains neither a value nor an error.
+// A present zero value (e.g. Ok(0)) is not empty.
+func (o Optional[T]) IsNone() bool {
+	return !o.present && !o.IsError()
+}
+
+// Alias of IsNone, matching Nothing.
+func (o Optional[T]) IsNothing() bool {
+	return o.IsNone()
+}
+
+// Map the carried value whether or not there is an error, e.g. the best-effort value GoOpt keeps on error.
+// The error and code are left untouched, only the value is mapped. Empty Optionals are returned unchanged.
+func (o Optional[T]) MapBoth(onValue func(T) T) Optional[T] {
+	if o.present {
+		o.Value = onValue(o.Value)
+	}
+	return o
+}
+
+// Drop the value of an error Optional (e.g. a large best-effort value from GoOpt) so it can be garbage collected.
+// The value is reset to the zero value and no longer reported as present. No-op without error.
+func (o Optional[T]) Compact() Optional[T] {
+	if o.IsError() {
+		var zero T
+		o.Value, o.present = zero, false
+	}
+	return o
+}
+
+// Recover from errors with a specific code by calling f with the error.
+// Values, empty Optionals and errors with another code are returned unchanged, so calls can be chained:
+//
+//	o.ThenIfCode(NOT_FOUND, useDefault).ThenIfCode(TIMEOUT, retry)
+func (o Optional[T]) ThenIfCode(code uint32, f func(error) Optional[T]) Optional[T] {
+	if !o.IsError() || o.ErrorCode != code {
+		return o
+	}
+	return f(o.Error)
+}
+
+// Pass a copy of o to sink, e.g. an audit log, and return o for further chaining.
+// The copy is shallow: sink cannot change o, but data referenced by the value (slices, maps, pointers) is shared.
+func (o Optional[T]) Tee(sink func(Optional[T])) Optional[T] {
+	sink(o)
+	return o
+}
+
+// Translate the error code with table, e.g. internal codes to public API codes at a boundary.
+// Unmapped codes, values and empty Optionals are returned unchanged, the error itself is kept.
+func (o Optional[T]) RemapCode(table map[uint32]uint32) Optional[T] {
+	if !o.IsError() {
+		return o
+	}
+	if code, ok := table[o.ErrorCode]; ok {
+		o.ErrorCode = code
+	}
+	return o
+}
+
+// Dispatch an error to the case for its code, or to dflt with error and code if no case matches,
+// e.g. to retry some codes and fail on others. A nil dflt returns unmatched errors unchanged.
+// Values and empty Optionals are returned unchanged.
+func (o Optional[T]) SwitchCode(cases map[uint32]func(error) Optional[T], dflt func(error, uint32) Optional[T]) Optional[T] {
+	if !o.IsError() {
+		return o
+	}
+	if f, ok := cases[o.ErrorCode]; ok {
+		return f(o.Error)
+	}
+	if dflt == nil {
+		return o
+	}
+	return dflt(o.Error, o.ErrorCode)
+}
+
+// Map the error code to an external code with table, e.g. gRPC or exit codes, or dflt if it is unmapped.
+// Values and empty Optionals return 0, the success code of such schemes (gRPC OK, exit status 0).
+func (o Optional[T]) MapToInt(table map[uint32]int, dflt int) int {
+	if !o.IsError() {
+		return 0
+	}
+	if mapped, ok := table[o.ErrorCode]; ok {
+		return mapped
+	}
+	return dflt
+}
+
+// Replace an error with the value v, values and empty Optionals are returned unchanged.
+// Unlike unwrapping with a default, the result stays an Optional for further chaining.
+func (o Optional[T]) Rescue(v T) Optional[T] {
+	if o.IsError() {
+		return Ok(v)
+	}
+	return o
+}
+
+// Call onOk with the value or onErr with the error and code, then return o unchanged,
+// e.g. for instrumentation at the end of a pipeline. Empty Optionals call neither, nil callbacks are skipped.
+func (o Optional[T]) Finally(onOk func(T), onErr func(error, uint32)) Optional[T] {
+	switch {
+	case o.IsError():
+		if onErr != nil {
+			onErr(o.Error, o.ErrorCode)
+		}
+	case o.present:
+		if onOk != nil {
+			onOk(o.Value)
+		}
+	}
+	return o
+}
+
+// Run the current error handlers again on the error, e.g. for Optionals created before SetErrorHandler.
+// Returns the possibly transformed Optional, a consumed error results in an empty Optional.
+// A best-effort value (see GoOpt) is kept if the result is still an error. Loggers registered with
+// RegisterCodeLogger are not called again. No-op without error and for code-only errors without Error.
+func (o Optional[T]) Rehandle() Optional[T] {
+	if !o.IsError() {
+		return o
+	}
+	if o.Error == nil { // code-only error, nothing for the handlers to convert
+		return o
+	}
+	// the code is already on o, so the strict check does not apply, and the loggers already saw the error
+	handled := handleErr[T](o.ErrorCode, o.Error)
+	if handled.IsError() {
+		handled.Value, handled.present = o.Value, o.present
+	}
+	return handled
+}
+
+// Attach a lower-level cause to the error, keeping the message of the error.
+// errors.Unwrap of the new error yields cause, errors.Is / errors.As match both the error and cause.
+// No-op without error.
+func (o Optional[T]) Because(cause error) Optional[T] {
+	if o.Error == nil || cause == nil {
+		return o
+	}
+	o.Error = &causeError{err: o.Error, cause: cause}
+	return o
+}
+
+// Error with an attached cause, see Optional.Because.
+type causeError struct {
+	err   error
+	cause error
+}
+
+func (e *causeError) Error() string        { return e.err.Error() }
+func (e *causeError) Unwrap() error        { return e.cause }
+func (e *causeError) Is(target error) bool { return errors.Is(e.err, target) }
+func (e *causeError) As(target any) bool   { return errors.As(e.err, target) }
+
 // String representation of the Optional, either the value or the error message.
 // Used by logging and formatting macros.
 func (o Optional[T]) String() string {
@@ -55,18 +352,127 @@ func (o Optional[T]) String() string {
 	return fmt.Sprintf("%v", o.Value)
 }
 
+// Get the error followed by its causes by repeatedly calling errors.Unwrap, nil without error.
+// Errors joined with errors.Join do not implement Unwrap() error and end the chain.
+func (o Optional[T]) UnwrapChain() []error {
+	var chain []error
+	for err := o.Error; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err)
+	}
+	return chain
+}
+
 // Convert to a tuple of (value, error) for use in traditional Go code.
 func (o Optional[T]) ToGo() (T, error) {
 	return o.Value, o.Error
 }
 
+// Same as ToGo, named to mark the end of a chain where the error is handled.
+func (o Optional[T]) Catch() (T, error) {
+	return o.ToGo()
+}
+
+// Like Catch, but also returns the error code that ToGo drops, 0 without error.
+func (o Optional[T]) CatchCode() (T, uint32, error) {
+	return o.Value, o.ErrorCode, o.Error
+}
+
+// Get the error and code as an error, e.g. to return it from a function returning error.
+// The code can be recovered with errors.As(err, &optErr) on a *OptionalError. Returns nil without error.
+func (o Optional[T]) AsError() error {
+	if !o.IsError() {
+		return nil
+	}
+	return &OptionalError{Err: o.Error, ErrorCode: o.ErrorCode}
+}
+
+// Box the value into an Optional[any], e.g. to keep Optionals of different types in one []Optional[any].
+// Error, code and presence are kept, an empty Optional boxes to a nil Value. Unbox with FromAny.
+func (o Optional[T]) Any() Optional[any] {
+	boxed := Optional[any]{Error: o.Error, ErrorCode: o.ErrorCode}
+	if o.present {
+		boxed.Value, boxed.present = o.Value, true
+	}
+	return boxed
+}
+
+// Get a closed channel holding o as its only element, e.g. to treat an immediate result
+// like an asynchronous one in a select.
+func (o Optional[T]) AsChan() <-chan Optional[T] {
+	ch := make(chan Optional[T], 1)
+	ch <- o
+	close(ch)
+	return ch
+}
+
+// Convert to a slice holding the value, or an empty slice on error or if empty.
+// A present zero value results in a slice with one element.
+func (o Optional[T]) ToSlice() []T {
+	if o.IsError() || !o.present {
+		return []T{}
+	}
+	return []T{o.Value}
+}
+
+// Comparable form of an Optional, see Optional.Comparable.
+type ComparableOptional[T any] struct {
+	Present bool
+	Value   T
+	Code    uint32
+	Message string
+}
+
+// Reduce to plain fields for comparisons in tests (e.g. cmp.Diff or reflect.DeepEqual),
+// the error is reduced to its message so error identity does not matter. Message is empty without error.
+func (o Optional[T]) Comparable() ComparableOptional[T] {
+	c := ComparableOptional[T]{Present: o.present, Value: o.Value, Code: o.ErrorCode}
+	if o.Error != nil {
+		c.Message = o.Error.Error()
+	}
+	return c
+}
+
+// Get value, error and presence at once, e.g. to destructure in a single line.
+// present reports if a value was supplied, which is also the case for the best-effort value of GoOpt on error.
+func Explode[T any](o Optional[T]) (value T, err error, present bool) {
+	return o.Value, o.Error, o.present
+}
+
+//*********************************************************************************
+//                              Rust-style Aliases
+//*********************************************************************************
+// Familiar names for users coming from Rust's Result. The Go names above are canonical.
+
+// Alias of !IsError, like Result::is_ok. Also true for empty Optionals.
+func (o Optional[T]) IsOk() bool {
+	return !o.IsError()
+}
+
+// Alias of IsError, like Result::is_err.
+func (o Optional[T]) IsErr() bool {
+	return o.IsError()
+}
+
+// Get the error, asserting that there is one, like Result::unwrap_err. Panics without error.
+func (o Optional[T]) UnwrapErr() error {
+	if !o.IsError() {
+		panic(fmt.Sprintf("UnwrapErr called on Optional without error: %v", o))
+	}
+	return o.Error
+}
+
 //*********************************************************************************
 //                              Optional Constructors
 //*********************************************************************************
 
 // Return a guaranteed value.
 func Ok[T any](value T) Optional[T] {
-	return Optional[T]{Value: value}
+	return Optional[T]{Value: value, present: true}
+}
+
+// Return a successful Optional[Void], the Void equivalent of Ok.
+func OkVoid() Optional[Void] {
+	return Ok(Void{})
 }
 
 // Return an error without a code.
@@ -75,9 +481,35 @@ func Err[T any](err any) Optional[T] {
 }
 
 // Return an error with a code.
+// If code is 0 and err is or wraps a CodedError, its Code() is used instead.
+// With SetStrictCodes(true), a reserved code (see RESERVED_CODE_MIN) returns an error with ARGUMENT_ERROR_CODE instead.
 func CodeErr[T any](code uint32, err any) Optional[T] {
-	if errorHandler != nil {
-		code, err = errorHandler(code, err)
+	if strictCodes && code >= RESERVED_CODE_MIN {
+		return codeErr[T](ARGUMENT_ERROR_CODE, fmt.Errorf("CodeErr: code %d is reserved for package optional, use a code below %d (error: %v)", code, uint32(RESERVED_CODE_MIN), err))
+	}
+	return codeErr[T](code, err)
+}
+
+// CodeErr without the strict code check, for errors with reserved codes raised by the package itself.
+func codeErr[T any](code uint32, err any) Optional[T] {
+	opt := handleErr[T](code, err)
+	logErrorCode(opt.ErrorCode, opt.Error)
+	return opt
+}
+
+// Run the error handlers and build the Optional, without calling the code loggers.
+func handleErr[T any](code uint32, err any) Optional[T] {
+	if typed_err, ok := err.(error); ok && code == 0 {
+		var coded CodedError
+		if errors.As(typed_err, &coded) {
+			code = coded.Code()
+		}
+	}
+	handlerMu.RLock()
+	handler, unknownHandler := errorHandler, unknownErrorHandler
+	handlerMu.RUnlock()
+	if handler != nil {
+		code, err = handler(code, err)
 	}
 	if code == 0 && err == nil { // error has been handled?
 		return Optional[T]{}
@@ -85,43 +517,162 @@ func CodeErr[T any](code uint32, err any) Optional[T] {
 	if code == PANIC_CODE {
 		panic(err)
 	}
+	var opt Optional[T]
 	switch typed_err := err.(type) {
 	case string:
-		return Optional[T]{Error: errors.New(typed_err), ErrorCode: code}
+		opt = Optional[T]{Error: newStringError(typed_err), ErrorCode: code}
 	case error:
-		return Optional[T]{Error: typed_err, ErrorCode: code}
+		opt = Optional[T]{Error: typed_err, ErrorCode: code}
 	default:
-		if unknownErrorHandler != nil {
-			code, err := unknownErrorHandler(PANIC_CODE, typed_err)
-			if code != PANIC_CODE {
-				return Optional[T]{Error: err, ErrorCode: code}
+		handlerMu.RLock()
+		typeHandler := errorTypeHandlers[reflect.TypeOf(typed_err)]
+		handlerMu.RUnlock()
+		if typeHandler != nil {
+			typeCode, typeErr := typeHandler(typed_err)
+			if typeCode == 0 {
+				typeCode = code
 			}
+			opt = Optional[T]{Error: typeErr, ErrorCode: typeCode}
+			break
+		}
+		if unknownHandler == nil {
+			panic(fmt.Sprintf("<Optional[T]>.Err called with unknown error type %T", typed_err))
 		}
-		panic(fmt.Sprintf("<Optional[T]>.Err called with unknown error type %T", typed_err))
+		code, err := unknownHandler(PANIC_CODE, typed_err)
+		if code == PANIC_CODE {
+			panic(fmt.Sprintf("<Optional[T]>.Err called with unknown error type %T", typed_err))
+		}
+		opt = Optional[T]{Error: err, ErrorCode: code}
 	}
+	return opt
+}
+
+// Return a formatted error without a code.
+// Formats with fmt.Errorf, so %w keeps the wrapped error reachable for errors.Is / errors.As.
+func Errf[T any](format string, args ...any) Optional[T] {
+	return CodeErr[T](0, fmt.Errorf(format, args...))
+}
+
+// Return a formatted error with a code.
+// Formats with fmt.Errorf, so %w keeps the wrapped error reachable for errors.Is / errors.As.
+func CodeErrf[T any](code uint32, format string, args ...any) Optional[T] {
+	return CodeErr[T](code, fmt.Errorf(format, args...))
 }
 
 // Pass the error or value from another Optional.
-// If value is passed, it is converted if possible, otherwise an error is returned.
+// If value is passed, it is converted if possible, otherwise Cast panics,
+// or returns an error with CAST_ERROR_CODE if disabled by SetCastPanics(false).
 func Cast[T any, U any](another Optional[U]) Optional[T] {
 	if another.IsError() {
 		return Optional[T]{Error: another.Error, ErrorCode: another.ErrorCode}
 	}
+	if !another.present {
+		return Optional[T]{}
+	}
 	if convertedValue, ok := any(another.Value).(T); ok {
 		return Ok(convertedValue)
 	} else {
-		return CodeErr[T](PANIC_CODE, fmt.Errorf("Cast[%T](%T) failed. Types are not compatible", another.Value, convertedValue))
+		code := uint32(CAST_ERROR_CODE)
+		if castPanics {
+			code = PANIC_CODE
+		}
+		return codeErr[T](code, fmt.Errorf("Cast[%T](%T) failed. Types are not compatible", another.Value, convertedValue))
+	}
+}
+
+// Unbox an Optional[any] created by Any. Errors and empty Optionals are passed through,
+// a value that is not a T returns an error with CAST_ERROR_CODE. Unlike Cast, this never panics.
+func FromAny[T any](o Optional[any]) Optional[T] {
+	if o.IsError() {
+		return Forward[T](o)
+	}
+	if !o.present {
+		return None[T]()
+	}
+	if v, ok := o.Value.(T); ok {
+		return Ok(v)
 	}
+	return codeErr[T](CAST_ERROR_CODE, fmt.Errorf("FromAny[%T]: value has type %T", *new(T), o.Value))
+}
+
+// Forward the error of another Optional regardless of its type, the error-only half of Cast:
+//
+//	if f.IsError() { return Forward[T](f) }
+//
+// Forwarding a non-error Optional is a programming error and panics via PANIC_CODE.
+func Forward[T any, U any](from Optional[U]) Optional[T] {
+	if !from.IsError() {
+		return codeErr[T](PANIC_CODE, fmt.Errorf("Forward[%T] called on an Optional without error", *new(T)))
+	}
+	return Optional[T]{Error: from.Error, ErrorCode: from.ErrorCode}
 }
 
 // Convert a traditional Go (value, error) return to an Optional.
 // Can wrap directly around a function call.
 func GoOpt[T any](value T, err error) Optional[T] {
-	if err != nil {
-		opt := Err[T](err)
-		opt.Value = value
+	return Make(value, true, err, 0)
+}
+
+// Low-level constructor covering all states at once, the error beats the presence of a value:
+//  1. err != nil or code != 0: an error routed through the error handlers like CodeErr.
+//     If present, value is kept as best-effort value (see GoOpt). A code without err gets a generic message.
+//  2. present: a value, also if it is the zero value.
+//  3. otherwise an empty Optional.
+func Make[T any](value T, present bool, err error, code uint32) Optional[T] {
+	if err != nil || code != 0 {
+		var opt Optional[T]
+		if err != nil {
+			opt = CodeErr[T](code, err)
+		} else {
+			opt = CodeErr[T](code, fmt.Sprintf("error code %d", code))
+		}
+		if present {
+			opt.Value, opt.present = value, true
+		}
 		return opt
 	}
+	if present {
+		return Ok(value)
+	}
+	return None[T]()
+}
+
+// Convert a traditional Go (value, value, error) return to an Optional of a Pair.
+// Behaves like GoOpt, on error both values are kept.
+func GoOpt2[A any, B any](first A, second B, err error) Optional[Pair[A, B]] {
+	return GoOpt(Pair[A, B]{First: first, Second: second}, err)
+}
+
+// Alias of None. Empty Optionals are plain values that do not allocate, every call is equivalent.
+func Nothing[T any]() Optional[T] {
+	return None[T]()
+}
+
+// Return an empty Optional if value is the zero value of T, otherwise a value.
+// Use to treat zero values like 0 or "" as absent.
+func NonZero[T comparable](value T) Optional[T] {
+	var zero T
+	if value == zero {
+		return None[T]()
+	}
+	return Ok(value)
+}
+
+// Return an empty Optional if value is "empty", otherwise a value. Empty are:
+// strings, slices and maps of length 0 (including nil), and nil pointers, interfaces, channels and functions.
+// Other values such as numbers, bools and structs are never empty, use NonZero to treat zero values as absent.
+func OkNonEmpty[T any](value T) Optional[T] {
+	v := reflect.ValueOf(&value).Elem()
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return None[T]()
+		}
+	case reflect.Pointer, reflect.Interface, reflect.Chan, reflect.Func:
+		if v.IsNil() {
+			return None[T]()
+		}
+	}
 	return Ok(value)
 }
 
@@ -130,6 +681,202 @@ func None[T any]() Optional[T] {
 	return Optional[T]{}
 }
 
+//*********************************************************************************
+//                             Optional Transformations
+//*********************************************************************************
+
+// Map the contained value with a function that can fail.
+// Errors and empty Optionals are passed through, f is only called on a value.
+// If f fails, the error is routed through GoOpt with code 0 (so the error handler can assign one),
+// and the value returned by f alongside the error is kept.
+func TryMap[T any, U any](o Optional[T], f func(T) (U, error)) Optional[U] {
+	if o.IsError() {
+		return Forward[U](o)
+	}
+	if !o.present {
+		return Optional[U]{}
+	}
+	return GoOpt(f(o.Value))
+}
+
+// Chain a function that returns an Optional itself, flattening the result.
+// Errors and empty Optionals are passed through, f is only called on a value.
+func AndThen[T any, U any](o Optional[T], f func(T) Optional[U]) Optional[U] {
+	if o.IsError() {
+		return Forward[U](o)
+	}
+	if !o.present {
+		return None[U]()
+	}
+	return f(o.Value)
+}
+
+// Alias of AndThen, the monadic bind. With Ok as return, the monad laws hold:
+//
+//	Bind(Ok(a), f)            == f(a)                                    // left identity
+//	Bind(m, Ok)               == m                                       // right identity
+//	Bind(Bind(m, f), g)       == Bind(m, func(x T) { Bind(f(x), g) })    // associativity
+//
+// Right identity drops a best-effort value carried by an error (see GoOpt).
+func Bind[T any, U any](o Optional[T], f func(T) Optional[U]) Optional[U] {
+	return AndThen(o, f)
+}
+
+// Pair the value with a key derived by f, e.g. before building a map from a stream of Optionals.
+// A function rather than a method because methods cannot introduce the key type K.
+// Errors and empty Optionals are passed through, f is only called on a value.
+func KeyBy[T any, K any](o Optional[T], f func(T) K) Optional[Pair[K, T]] {
+	return AndThen(o, func(v T) Optional[Pair[K, T]] { return Ok(Pair[K, T]{First: f(v), Second: v}) })
+}
+
+// Split an Optional of a Pair into its components.
+// An error (with its code) is copied into both results, an empty Optional gives two empty ones.
+func Unzip[A any, B any](o Optional[Pair[A, B]]) (Optional[A], Optional[B]) {
+	if o.IsError() {
+		return Forward[A](o), Forward[B](o)
+	}
+	if !o.present {
+		return None[A](), None[B]()
+	}
+	return Ok(o.Value.First), Ok(o.Value.Second)
+}
+
+// Negate o for validation chains that fail if something is present, e.g. an existing user name:
+// a value becomes an error naming the value, an error becomes OkVoid().
+// An empty Optional results in the error returned by onNone, or OkVoid() if it returns nil or onNone is nil.
+func Invert[T any](o Optional[T], onNone func() error) Optional[Void] {
+	switch {
+	case o.IsError():
+		return OkVoid()
+	case o.present:
+		return Errf[Void]("Invert: unexpected value %v", o.Value)
+	}
+	if onNone == nil {
+		return OkVoid()
+	}
+	if err := onNone(); err != nil {
+		return Err[Void](err)
+	}
+	return OkVoid()
+}
+
+// Extract a field (or any derived value) from the contained value.
+// Errors and empty Optionals are passed through, get is only called on a value.
+func Pluck[T any, F any](o Optional[T], get func(T) F) Optional[F] {
+	return AndThen(o, func(v T) Optional[F] { return Ok(get(v)) })
+}
+
+// Extract a field that is an Optional itself, e.g. an optional struct field.
+// Like AndThen, the field's error or emptiness becomes the result.
+func PluckOpt[T any, F any](o Optional[T], get func(T) Optional[F]) Optional[F] {
+	return AndThen(o, get)
+}
+
+// Recover from an error by computing an alternative Optional from the error and its code.
+// f is only called on error and may return another error. Values and empty Optionals are returned unchanged.
+func OrElseErr[T any](o Optional[T], f func(error, uint32) Optional[T]) Optional[T] {
+	if !o.IsError() {
+		return o
+	}
+	return f(o.Error, o.ErrorCode)
+}
+
+// Convert the contained number to another numeric type.
+// Errors and empty Optionals are passed through.
+// A conversion that does not round-trip exactly (overflow, sign change, lost fraction or precision)
+// returns an error with CONVERSION_ERROR_CODE.
+func ConvertNumeric[U Number, T Number](o Optional[T]) Optional[U] {
+	if o.IsError() {
+		return Forward[U](o)
+	}
+	if !o.present {
+		return Optional[U]{}
+	}
+	converted := U(o.Value)
+	if T(converted) != o.Value || (o.Value < 0) != (converted < 0) {
+		return codeErr[U](CONVERSION_ERROR_CODE, fmt.Errorf("ConvertNumeric: %v does not fit into %T", o.Value, converted))
+	}
+	return Ok(converted)
+}
+
+// Flatten an Optional of a pointer, e.g. from a pointer returning API.
+// A non-nil pointer results in its dereferenced value, a nil pointer in an empty Optional.
+// Errors are passed through.
+func DerefOptional[T any](o Optional[*T]) Optional[T] {
+	if o.IsError() {
+		return Forward[T](o)
+	}
+	if o.Value == nil {
+		return None[T]()
+	}
+	return Ok(*o.Value)
+}
+
+//*********************************************************************************
+//                               Optional Queries
+//*********************************************************************************
+
+// Returns if o contains a slice holding v, false on error or if empty.
+func ContainsValue[T comparable](o Optional[[]T], v T) bool {
+	if o.IsError() || !o.present {
+		return false
+	}
+	return slices.Contains(o.Value, v)
+}
+
+// Returns if o contains a map holding the key k, false on error or if empty.
+func ContainsKey[K comparable, V any](o Optional[map[K]V], k K) bool {
+	if o.IsError() || !o.present {
+		return false
+	}
+	_, ok := o.Value[k]
+	return ok
+}
+
+// Look up k in m and apply f to the value, e.g. to resolve a config entry.
+// A missing key returns an error with NOT_FOUND_ERROR_CODE naming the key.
+func MapLookupThen[K comparable, V any, U any](m map[K]V, k K, f func(V) Optional[U]) Optional[U] {
+	v, ok := m[k]
+	if !ok {
+		return codeErr[U](NOT_FOUND_ERROR_CODE, fmt.Errorf("MapLookupThen: key %v not found", k))
+	}
+	return f(v)
+}
+
+// Compare two Optionals for sorting, e.g. with slices.SortFunc. Returns -1, 0 or +1 for the total order:
+//
+//	empty < values (ordered by cmp.Compare) < errors (ordered by ErrorCode, then by message)
+//
+// Errors with the same code and message compare equal, best-effort values of errors are ignored.
+func CompareOpt[T cmp.Ordered](a, b Optional[T]) int {
+	rank := func(o Optional[T]) int {
+		switch {
+		case o.IsError():
+			return 2
+		case o.present:
+			return 1
+		}
+		return 0
+	}
+	if c := cmp.Compare(rank(a), rank(b)); c != 0 || rank(a) == 0 {
+		return c
+	}
+	if rank(a) == 1 {
+		return cmp.Compare(a.Value, b.Value)
+	}
+	if c := cmp.Compare(a.ErrorCode, b.ErrorCode); c != 0 {
+		return c
+	}
+	return cmp.Compare(errorMessage(a.Error), errorMessage(b.Error))
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 //*********************************************************************************
 //                            Optional Factory (Opt)
 //*********************************************************************************
@@ -144,11 +891,45 @@ func (Opt[T]) Err(err any) Optional[T]             { return Err[T](err) }
 func (Opt[T]) CodeErr(code uint32, err any) Optional[T] { return CodeErr[T](code, err) }
 func (Opt[T]) None() Optional[T]                            { return Optional[T]{} }
 
+// Opt[T] factory that additionally carries a default value for T.
+// Use to centralize the fallback of repeated unwrapping:
+//
+//	d := WithDefault(-1)
+//	port := d.Or(ParsePort(s)) // -1 on error or if empty
+type OptD[T any] struct {
+	Opt[T]
+	Default T
+}
+
+// Create a factory with the given default value.
+func WithDefault[T any](value T) OptD[T] {
+	return OptD[T]{Default: value}
+}
+
+// Get the value of o, or the default on error or if o is empty.
+// A present zero value is returned as is.
+func (d OptD[T]) Or(o Optional[T]) T {
+	if o.IsError() || !o.present {
+		return d.Default
+	}
+	return o.Value
+}
+
 type ErrorHandler func(code uint32, err any) (uint32, error)
 type UnknownErrorHandler func(code uint32, err any) (uint32, error)
 
+// Guards errorHandler, unknownErrorHandler, codeLoggers, panicHook, errorTypeHandlers and unwrapObserver.
+var handlerMu sync.RWMutex
 var errorHandler ErrorHandler = nil
 var unknownErrorHandler UnknownErrorHandler = nil
+var codeLoggers = map[uint32]func(error){}
+var panicHook func(recovered any, stack []byte) = nil
+var errorTypeHandlers = map[reflect.Type]func(any) (uint32, error){}
+var unwrapObserver func(err error, code uint32) = nil
+var castPanics = true
+var strictCodes = false
+var internErrors = false
+var internedErrors sync.Map // string -> error
 
 //*********************************************************************************
 //                             Custom Error Handlers
@@ -156,9 +937,108 @@ var unknownErrorHandler UnknownErrorHandler = nil
 
 // set an error handler that can modify and consume errors.
 func SetErrorHandler(handler ErrorHandler) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
 	errorHandler = handler
 }
 
 func SetUnknownErrorHandler(handler UnknownErrorHandler) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
 	unknownErrorHandler = handler
 }
+
+// Set a function that is called with the error and code whenever Unwrap is about to panic,
+// e.g. to count failed unwraps or record a trace. nil (the default) disables it.
+func SetUnwrapObserver(observer func(err error, code uint32)) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	unwrapObserver = observer
+}
+
+func notifyUnwrapObserver(err error, code uint32) {
+	handlerMu.RLock()
+	observer := unwrapObserver
+	handlerMu.RUnlock()
+	if observer != nil {
+		observer(err, code)
+	}
+}
+
+// Register a converter for error values of type t that are neither string nor error, e.g. int codes
+// or status structs. CodeErr consults it before the UnknownErrorHandler. If fn returns code 0,
+// the code passed to CodeErr is kept. Replaces a previously registered converter, nil removes it.
+func RegisterErrorType(t reflect.Type, fn func(any) (uint32, error)) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	if fn == nil {
+		delete(errorTypeHandlers, t)
+	} else {
+		errorTypeHandlers[t] = fn
+	}
+}
+
+// Set a function that is called with the recovered value and stack trace
+// whenever RecoverHandler converts a panic, e.g. to log it. nil removes it.
+func SetPanicHook(hook func(recovered any, stack []byte)) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	panicHook = hook
+}
+
+// Register a function that is called with the error whenever CodeErr creates an error with code,
+// i.e. after the error handlers have run. Replaces a previously registered function, nil removes it.
+func RegisterCodeLogger(code uint32, logFn func(error)) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	if logFn == nil {
+		delete(codeLoggers, code)
+	} else {
+		codeLoggers[code] = logFn
+	}
+}
+
+func logErrorCode(code uint32, err error) {
+	if code == 0 {
+		return
+	}
+	handlerMu.RLock()
+	logFn := codeLoggers[code]
+	handlerMu.RUnlock()
+	if logFn != nil {
+		logFn(err)
+	}
+}
+
+// Choose whether Cast panics on incompatible types (default) or returns an error with CAST_ERROR_CODE.
+// This is global state: set it once at startup, flipping it while other goroutines call Cast is not safe.
+func SetCastPanics(panics bool) {
+	castPanics = panics
+}
+
+// Choose whether errors created from strings share one error instance per message, saving
+// an allocation per error in hot paths. Interned errors compare equal with ==.
+// The cache is never pruned, so only enable it for a bounded set of messages, not for formatted ones.
+// This is global state: set it once at startup, like SetCastPanics.
+func SetErrorInterning(intern bool) {
+	internErrors = intern
+}
+
+func newStringError(msg string) error {
+	if !internErrors {
+		return errors.New(msg)
+	}
+	if err, ok := internedErrors.Load(msg); ok {
+		return err.(error)
+	}
+	err, _ := internedErrors.LoadOrStore(msg, errors.New(msg))
+	return err.(error)
+}
+
+// Choose whether CodeErr rejects reserved codes passed by callers, including PANIC_CODE.
+// If enabled, such a call returns an error with ARGUMENT_ERROR_CODE naming the code instead of
+// panicking or posing as a package error. Codes assigned by the package itself and by an ErrorHandler are unaffected.
+// This is global state: set it once at startup, like SetCastPanics.
+func SetStrictCodes(strict bool) {
+	strictCodes = strict
+}
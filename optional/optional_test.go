@@ -1,25 +1,39 @@
 package optional
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 )
 
+type codedTestError struct{ code uint32 }
+
+func (e codedTestError) Error() string { return fmt.Sprintf("coded %d", e.code) }
+func (e codedTestError) Code() uint32  { return e.code }
+
 // helper to ensure a function panics and to capture its value
-func mustPanic(t *testing.T, f func()) any {
+func mustPanic(t *testing.T, f func()) (recovered any) {
 	 t.Helper()
 	 defer func() {
-		 if r := recover(); r == nil {
+		 if recovered = recover(); recovered == nil {
 			 t.Fatalf("expected panic but none occurred")
 		 }
 	 }()
-	 var got any
-	 func() { // separate to capture recovered value cleanly
-		 defer func() { got = recover(); panic(got) }()
-		 f()
-	 }()
-	 return nil // unreachable
+	 f()
+	 return nil
+}
+
+// Get IsNone, IsSomeStrict and IsError of o.
+func states[T any](o Optional[T]) [3]bool {
+	 return [3]bool{o.IsNone(), o.IsSomeStrict(), o.IsError()}
 }
 
 func TestOptional(t *testing.T) {
@@ -137,4 +151,675 @@ func TestOptional(t *testing.T) {
 		 e2 := oStr.CodeErr(7, fmt.Errorf("x"))
 		 if e2.ErrorCode != 7 || e2.Error == nil { t.Fatalf("expected string opt code 7") }
 	 })
+
+	 t.Run("TryMap", func(t *testing.T) {
+		 parsed := TryMap(Ok("42"), strconv.Atoi)
+		 if parsed.IsError() || parsed.Value != 42 { t.Fatalf("expected 42, got %v", parsed) }
+
+		 failed := TryMap(Ok("x"), strconv.Atoi)
+		 if !failed.IsError() { t.Fatalf("expected parse error") }
+		 if failed.ErrorCode != 0 { t.Fatalf("expected code 0, got %d", failed.ErrorCode) }
+
+		 called := false
+		 forwarded := TryMap(CodeErr[string](9, "up"), func(s string) (int, error) { called = true; return 0, nil })
+		 if called { t.Fatalf("f must not run on error") }
+		 if forwarded.ErrorCode != 9 || forwarded.Error.Error() != "up" { t.Fatalf("error not forwarded: %v", forwarded) }
+
+		 none := TryMap(None[string](), func(s string) (int, error) { called = true; return 1, nil })
+		 if called || none.IsError() || none.IsSome() { t.Fatalf("none not forwarded") }
+	 })
+
+	 t.Run("OkVoid is some", func(t *testing.T) {
+		 opt := OkVoid()
+		 if opt.IsError() { t.Fatalf("unexpected error") }
+		 if !opt.IsSome() { t.Fatalf("OkVoid should be some despite Void{} being zero") }
+		 if None[Void]().IsSome() { t.Fatalf("None[Void] should not be some") }
+	 })
+
+	 t.Run("TryMap zero value is present", func(t *testing.T) {
+		 opt := TryMap(Ok(0), func(i int) (string, error) { return strconv.Itoa(i), nil })
+		 if opt.IsError() || opt.Value != "0" { t.Fatalf("expected \"0\", got %v", opt) }
+	 })
+
+	 t.Run("Cast None stays None", func(t *testing.T) {
+		 opt := Cast[int](None[string]())
+		 if opt.IsError() || opt.present { t.Fatalf("expected none, got %v", opt) }
+	 })
+
+	 t.Run("GoOpt2 pairs values", func(t *testing.T) {
+		 opt := GoOpt2(net.SplitHostPort("localhost:8080"))
+		 if opt.IsError() { t.Fatalf("unexpected error: %v", opt.Error) }
+		 if opt.Value.First != "localhost" || opt.Value.Second != "8080" { t.Fatalf("unexpected pair: %+v", opt.Value) }
+	 })
+
+	 t.Run("GoOpt2 forwards error", func(t *testing.T) {
+		 opt := GoOpt2(net.SplitHostPort("no-port"))
+		 if !opt.IsError() { t.Fatalf("expected error") }
+		 var addrErr *net.AddrError
+		 if !errors.As(opt.Error, &addrErr) { t.Fatalf("expected *net.AddrError, got %T", opt.Error) }
+	 })
+
+	 t.Run("IsNone, IsSomeStrict and IsError are exclusive", func(t *testing.T) {
+		 cases := map[string][3]bool{
+			 "Ok":             states(Ok(42)),
+			 "Ok(0)":          states(Ok(0)),
+			 "Ok(\"\")":         states(Ok("")),
+			 "OkVoid":         states(OkVoid()),
+			 "Err":            states(Err[int]("e")),
+			 "CodeErr":        states(CodeErr[int](3, "e")),
+			 "GoOpt value":    states(GoOpt(5, nil)),
+			 "GoOpt error":    states(GoOpt(5, errors.New("e"))),
+			 "None":           states(None[int]()),
+			 "Opt.None":       states(Opt[int]{}.None()),
+			 "Cast":           states(Cast[int](Ok(1))),
+		 }
+		 for name, s := range cases {
+			 count := 0
+			 for _, b := range s { if b { count++ } }
+			 if count != 1 { t.Fatalf("%s: expected exactly one state, got none=%v some=%v error=%v", name, s[0], s[1], s[2]) }
+		 }
+		 if !OkVoid().IsSome() || OkVoid().IsNone() { t.Fatalf("OkVoid must be some") }
+		 if Ok(0).IsNone() { t.Fatalf("present zero value must not be none") }
+	 })
+
+	 t.Run("Errf wraps with %w", func(t *testing.T) {
+		 cause := errors.New("disk full")
+		 opt := Errf[int]("write %s: %w", "file.txt", cause)
+		 if !opt.IsError() || opt.ErrorCode != 0 { t.Fatalf("expected uncoded error") }
+		 if opt.Error.Error() != "write file.txt: disk full" { t.Fatalf("unexpected message: %v", opt.Error) }
+		 if !errors.Is(opt.Error, cause) { t.Fatalf("expected wrapped cause") }
+	 })
+
+	 t.Run("CodeErrf keeps code and routes through handler", func(t *testing.T) {
+		 prev := errorHandler
+		 defer func(){ errorHandler = prev }()
+		 SetErrorHandler(func(code uint32, err any) (uint32, error) { return code + 1, err.(error) })
+		 cause := errors.New("timeout")
+		 opt := CodeErrf[string](10, "fetch: %w", cause)
+		 if opt.ErrorCode != 11 { t.Fatalf("expected handled code 11, got %d", opt.ErrorCode) }
+		 if !errors.Is(opt.Error, cause) { t.Fatalf("expected wrapped cause") }
+	 })
+
+	 t.Run("Cast without panics returns coded error", func(t *testing.T) {
+		 defer SetCastPanics(true)
+		 SetCastPanics(false)
+		 opt := Cast[int](Ok("hi"))
+		 if !opt.IsError() { t.Fatalf("expected error") }
+		 if opt.ErrorCode != CAST_ERROR_CODE { t.Fatalf("expected CAST_ERROR_CODE, got %d", opt.ErrorCode) }
+		 if ok := Cast[string](Ok("hi")); ok.IsError() || ok.Value != "hi" { t.Fatalf("compatible cast must still succeed") }
+
+		 SetCastPanics(true)
+		 if r := mustPanic(t, func(){ Cast[int](Ok("hi")) }); !strings.Contains(fmt.Sprint(r), "Types are not compatible") { t.Fatalf("unexpected panic value %v", r) }
+	 })
+
+	 t.Run("CodedError supplies its code", func(t *testing.T) {
+		 opt := Err[int](codedTestError{code: 404})
+		 if opt.ErrorCode != 404 { t.Fatalf("expected code 404 from CodedError, got %d", opt.ErrorCode) }
+
+		 wrapped := Errf[int]("lookup: %w", codedTestError{code: 405})
+		 if wrapped.ErrorCode != 405 { t.Fatalf("expected code 405 from wrapped CodedError, got %d", wrapped.ErrorCode) }
+
+		 explicit := CodeErr[int](500, codedTestError{code: 404})
+		 if explicit.ErrorCode != 500 { t.Fatalf("explicit code must win, got %d", explicit.ErrorCode) }
+	 })
+
+	 t.Run("ToSlice", func(t *testing.T) {
+		 if got := Ok(3).ToSlice(); len(got) != 1 || got[0] != 3 { t.Fatalf("expected [3], got %v", got) }
+		 if got := Ok(0).ToSlice(); len(got) != 1 { t.Fatalf("present zero value must yield one element, got %v", got) }
+		 if got := Err[int]("e").ToSlice(); got == nil || len(got) != 0 { t.Fatalf("expected empty slice for error, got %v", got) }
+		 if got := GoOpt(4, errors.New("e")).ToSlice(); len(got) != 0 { t.Fatalf("expected empty slice for error with value, got %v", got) }
+		 if got := None[int]().ToSlice(); len(got) != 0 { t.Fatalf("expected empty slice for none, got %v", got) }
+	 })
+
+	 t.Run("ConvertNumeric widening", func(t *testing.T) {
+		 opt := ConvertNumeric[int64](Ok(int32(-7)))
+		 if opt.IsError() || opt.Value != -7 { t.Fatalf("expected -7, got %v", opt) }
+		 f := ConvertNumeric[float64](Ok(3))
+		 if f.IsError() || f.Value != 3.0 { t.Fatalf("expected 3.0, got %v", f) }
+	 })
+
+	 t.Run("ConvertNumeric lossy narrowing", func(t *testing.T) {
+		 if opt := ConvertNumeric[int8](Ok(300)); opt.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected overflow error, got %v", opt) }
+		 if opt := ConvertNumeric[uint64](Ok(-1)); opt.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected sign error, got %v", opt) }
+		 if opt := ConvertNumeric[int](Ok(2.5)); opt.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("expected fraction error, got %v", opt) }
+		 if opt := ConvertNumeric[uint8](Ok(255)); opt.IsError() || opt.Value != 255 { t.Fatalf("expected 255 to fit, got %v", opt) }
+	 })
+
+	 t.Run("ConvertNumeric forwards error and none", func(t *testing.T) {
+		 if opt := ConvertNumeric[int64](CodeErr[int](8, "e")); opt.ErrorCode != 8 { t.Fatalf("expected forwarded code 8, got %v", opt) }
+		 if opt := ConvertNumeric[int64](None[int]()); !opt.IsNone() { t.Fatalf("expected none, got %v", opt) }
+	 })
+
+	 t.Run("UnwrapLog", func(t *testing.T) {
+		 var buf bytes.Buffer
+		 logger := slog.New(slog.NewTextHandler(&buf, nil))
+		 if v := Ok(5).UnwrapLog(logger); v != 5 || buf.Len() != 0 { t.Fatalf("expected silent 5, got %v / %q", v, buf.String()) }
+		 if r := mustPanic(t, func(){ CodeErr[int](21, "broken").UnwrapLog(logger) }); fmt.Sprint(r) != "broken" { t.Fatalf("expected panic with the error, got %v", r) }
+		 if !strings.Contains(buf.String(), "level=ERROR") || !strings.Contains(buf.String(), "error=broken") || !strings.Contains(buf.String(), "code=21") { t.Fatalf("unexpected log: %q", buf.String()) }
+	 })
+
+	 t.Run("WithDefault", func(t *testing.T) {
+		 d := WithDefault(-1)
+		 if v := d.Or(Ok(8)); v != 8 { t.Fatalf("expected 8, got %d", v) }
+		 if v := d.Or(Ok(0)); v != 0 { t.Fatalf("expected present zero value, got %d", v) }
+		 if v := d.Or(Err[int]("e")); v != -1 { t.Fatalf("expected default for error, got %d", v) }
+		 if v := d.Or(d.None()); v != -1 { t.Fatalf("expected default for none, got %d", v) }
+		 if e := d.CodeErr(3, "e"); e.ErrorCode != 3 { t.Fatalf("expected factory methods on OptD") }
+	 })
+
+	 t.Run("Explode", func(t *testing.T) {
+		 if v, err, present := Explode(Ok(0)); v != 0 || err != nil || !present { t.Fatalf("ok: got %v %v %v", v, err, present) }
+		 if v, err, present := Explode(Err[int]("e")); v != 0 || err == nil || present { t.Fatalf("error: got %v %v %v", v, err, present) }
+		 if v, err, present := Explode(GoOpt(3, errors.New("e"))); v != 3 || err == nil || !present { t.Fatalf("error with value: got %v %v %v", v, err, present) }
+		 if v, err, present := Explode(None[int]()); v != 0 || err != nil || present { t.Fatalf("none: got %v %v %v", v, err, present) }
+	 })
+
+	 t.Run("Comparable", func(t *testing.T) {
+		 if Err[int]("same").Comparable() != Err[int]("same").Comparable() { t.Fatalf("errors with equal message must compare equal") }
+		 if CodeErr[int](1, "same").Comparable() == CodeErr[int](2, "same").Comparable() { t.Fatalf("different codes must differ") }
+		 c := Ok(0).Comparable()
+		 if !c.Present || c.Message != "" || c.Code != 0 { t.Fatalf("unexpected comparable for Ok(0): %+v", c) }
+		 if Ok(0).Comparable() == None[int]().Comparable() { t.Fatalf("present zero value must differ from none") }
+	 })
+
+	 t.Run("Forward", func(t *testing.T) {
+		 cause := errors.New("not found")
+		 opt := Forward[string](CodeErr[int](44, cause))
+		 if opt.ErrorCode != 44 || opt.Error != cause { t.Fatalf("expected forwarded error, got %v", opt) }
+		 if opt.present { t.Fatalf("forwarded error must not carry a value") }
+		 if r := mustPanic(t, func(){ Forward[string](Ok(1)) }); !strings.Contains(fmt.Sprint(r), "called on an Optional without error") { t.Fatalf("unexpected panic value %v", r) }
+		 if r := mustPanic(t, func(){ Forward[string](None[int]()) }); r == nil { t.Fatalf("expected panic for none") }
+	 })
+
+	 t.Run("DerefOptional", func(t *testing.T) {
+		 v := 0
+		 if opt := DerefOptional(Ok(&v)); opt.IsError() || opt.IsNone() || opt.Value != 0 { t.Fatalf("expected present 0, got %v", opt) }
+		 if opt := DerefOptional(Ok[*int](nil)); !opt.IsNone() { t.Fatalf("expected none for nil pointer, got %v", opt) }
+		 if opt := DerefOptional(CodeErr[*int](6, "e")); opt.ErrorCode != 6 { t.Fatalf("expected forwarded error, got %v", opt) }
+	 })
+
+	 t.Run("ContainsValue", func(t *testing.T) {
+		 if !ContainsValue(Ok([]string{"a", "b"}), "b") { t.Fatalf("expected b to be contained") }
+		 if ContainsValue(Ok([]string{"a"}), "c") { t.Fatalf("c must not be contained") }
+		 if ContainsValue(GoOpt([]string{"a"}, errors.New("e")), "a") { t.Fatalf("error must short-circuit") }
+		 if ContainsValue(None[[]string](), "") { t.Fatalf("none must short-circuit") }
+	 })
+
+	 t.Run("ContainsKey", func(t *testing.T) {
+		 m := map[string]int{"a": 0}
+		 if !ContainsKey(Ok(m), "a") { t.Fatalf("expected key a") }
+		 if ContainsKey(Ok(m), "b") { t.Fatalf("key b must not be contained") }
+		 if ContainsKey(GoOpt(m, errors.New("e")), "a") { t.Fatalf("error must short-circuit") }
+		 if ContainsKey(None[map[string]int](), "a") { t.Fatalf("none must short-circuit") }
+	 })
+
+	 t.Run("UnwrapOrZero", func(t *testing.T) {
+		 if v := Ok(5).UnwrapOrZero(); v != 5 { t.Fatalf("expected 5, got %d", v) }
+		 if v := Ok(0).UnwrapOrZero(); v != 0 { t.Fatalf("expected 0, got %d", v) }
+		 if v := GoOpt(5, errors.New("e")).UnwrapOrZero(); v != 0 { t.Fatalf("expected zero on error, got %d", v) }
+		 if v := None[string]().UnwrapOrZero(); v != "" { t.Fatalf("expected zero for none, got %q", v) }
+	 })
+
+	 t.Run("UnwrapChain", func(t *testing.T) {
+		 root := errors.New("connection refused")
+		 mid := fmt.Errorf("dial: %w", root)
+		 top := fmt.Errorf("fetch config: %w", mid)
+		 chain := Err[int](top).UnwrapChain()
+		 if len(chain) != 3 || chain[0] != top || chain[1] != mid || chain[2] != root { t.Fatalf("unexpected chain: %v", chain) }
+
+		 single := Err[int]("plain").UnwrapChain()
+		 if len(single) != 1 || single[0].Error() != "plain" { t.Fatalf("unexpected chain: %v", single) }
+
+		 if Ok(1).UnwrapChain() != nil { t.Fatalf("expected nil chain without error") }
+	 })
+
+	 t.Run("Rehandle applies handler installed later", func(t *testing.T) {
+		 opt := CodeErr[int](5, "late")
+		 prev := errorHandler
+		 defer func(){ errorHandler = prev }()
+		 SetErrorHandler(func(code uint32, err any) (uint32, error) { return code * 10, fmt.Errorf("handled: %v", err) })
+		 re := opt.Rehandle()
+		 if re.ErrorCode != 50 || re.Error.Error() != "handled: late" { t.Fatalf("unexpected rehandled Optional: %v (%d)", re, re.ErrorCode) }
+		 if ok := Ok(1).Rehandle(); ok.IsError() || ok.Value != 1 { t.Fatalf("Rehandle must be a no-op on success") }
+	 })
+
+	 t.Run("Rehandle consumed error becomes none", func(t *testing.T) {
+		 opt := GoOpt(3, errors.New("ignored"))
+		 prev := errorHandler
+		 defer func(){ errorHandler = prev }()
+		 SetErrorHandler(func(code uint32, err any) (uint32, error) { return 0, nil })
+		 if re := opt.Rehandle(); !re.IsNone() { t.Fatalf("expected none, got %v", re) }
+	 })
+
+	 t.Run("Rehandle keeps code-only errors and does not log again", func(t *testing.T) {
+		 codeOnly := Optional[int]{ErrorCode: 5}
+		 if re := codeOnly.Rehandle(); re != codeOnly { t.Fatalf("expected code-only error unchanged, got %v", re) }
+		 logged := 0
+		 RegisterCodeLogger(77, func(error) { logged++ })
+		 defer RegisterCodeLogger(77, nil)
+		 opt := CodeErr[int](77, "once")
+		 if re := opt.Rehandle(); re.ErrorCode != 77 || logged != 1 { t.Fatalf("expected a single log call, got %d for %v", logged, re) }
+	 })
+
+	 t.Run("NonZero", func(t *testing.T) {
+		 if o := NonZero(0); !o.IsNone() { t.Fatalf("expected none for 0, got %v", o) }
+		 if o := NonZero(3); o.IsNone() || o.Value != 3 { t.Fatalf("expected 3, got %v", o) }
+		 if o := NonZero(""); !o.IsNone() { t.Fatalf("expected none for empty string, got %v", o) }
+		 if o := NonZero("x"); o.IsNone() || o.Value != "x" { t.Fatalf("expected x, got %v", o) }
+	 })
+
+	 t.Run("Because", func(t *testing.T) {
+		 top := errors.New("save user")
+		 cause := errors.New("connection reset")
+		 opt := CodeErr[int](3, top).Because(cause)
+		 if opt.Error.Error() != "save user" || opt.ErrorCode != 3 { t.Fatalf("message and code must be kept, got %v (%d)", opt, opt.ErrorCode) }
+		 if errors.Unwrap(opt.Error) != cause { t.Fatalf("expected errors.Unwrap to yield cause") }
+		 if !errors.Is(opt.Error, cause) || !errors.Is(opt.Error, top) { t.Fatalf("expected errors.Is to match error and cause") }
+		 var coded codedTestError
+		 if !errors.As(Err[int](codedTestError{code: 1}).Because(cause).Error, &coded) { t.Fatalf("expected errors.As to match the error") }
+		 if ok := Ok(1).Because(cause); ok.IsError() { t.Fatalf("Because must be a no-op on success") }
+	 })
+
+	 t.Run("Rescue", func(t *testing.T) {
+		 if o := CodeErr[int](4, "e").Rescue(10); o.IsError() || o.Value != 10 { t.Fatalf("expected rescued 10, got %v", o) }
+		 if o := Ok(3).Rescue(10); o.Value != 3 { t.Fatalf("expected passthrough 3, got %v", o) }
+		 if o := None[int]().Rescue(10); !o.IsNone() { t.Fatalf("expected none passthrough, got %v", o) }
+	 })
+
+	 t.Run("RegisterCodeLogger", func(t *testing.T) {
+		 var authLogged, dbLogged []error
+		 RegisterCodeLogger(401, func(err error) { authLogged = append(authLogged, err) })
+		 RegisterCodeLogger(503, func(err error) { dbLogged = append(dbLogged, err) })
+		 defer RegisterCodeLogger(401, nil)
+		 defer RegisterCodeLogger(503, nil)
+
+		 CodeErr[int](401, "token expired")
+		 Err[int]("uncoded")
+		 CodeErr[int](402, "other")
+		 if len(authLogged) != 1 || authLogged[0].Error() != "token expired" { t.Fatalf("expected one auth log, got %v", authLogged) }
+		 if len(dbLogged) != 0 { t.Fatalf("unexpected db log: %v", dbLogged) }
+
+		 RegisterCodeLogger(401, nil)
+		 CodeErr[int](401, "again")
+		 if len(authLogged) != 1 { t.Fatalf("removed logger must not be called") }
+	 })
+
+	 t.Run("MapBoth", func(t *testing.T) {
+		 trim := func(s string) string { return strings.TrimSpace(s) }
+		 partial := GoOpt(" partial ", errors.New("truncated")).MapBoth(trim)
+		 if partial.Value != "partial" || partial.Error.Error() != "truncated" { t.Fatalf("expected mapped value with error kept, got %q / %v", partial.Value, partial.Error) }
+		 if o := Ok(" v ").MapBoth(trim); o.Value != "v" || o.IsError() { t.Fatalf("expected mapped value, got %q", o.Value) }
+		 called := false
+		 if o := Err[string]("e").MapBoth(func(s string) string { called = true; return s }); called || o.Error == nil { t.Fatalf("onValue must not run without value") }
+	 })
+
+	 t.Run("Compact", func(t *testing.T) {
+		 opt := GoOpt(make([]byte, 1024), errors.New("short read")).Compact()
+		 if opt.Value != nil || opt.present { t.Fatalf("expected cleared value and presence, got %d bytes, present=%v", len(opt.Value), opt.present) }
+		 if opt.Error == nil || opt.Error.Error() != "short read" { t.Fatalf("error must be kept") }
+		 if _, _, present := Explode(opt); present { t.Fatalf("Explode must report no value") }
+		 if o := Ok(2).Compact(); o.Value != 2 || !o.present { t.Fatalf("Compact must be a no-op on success") }
+	 })
+
+	 t.Run("IsSomeStrict", func(t *testing.T) {
+		 if !Ok(0).IsSomeStrict() { t.Fatalf("Ok(0) must be strictly some") }
+		 if Ok(0).IsSome() { t.Fatalf("Ok(0).IsSome() must stay false") }
+		 if !Ok(1).IsSomeStrict() || !OkVoid().IsSomeStrict() { t.Fatalf("values must be strictly some") }
+		 if None[int]().IsSomeStrict() || GoOpt(1, errors.New("e")).IsSomeStrict() { t.Fatalf("none and errors must not be strictly some") }
+		 for _, opt := range []Optional[int]{Ok(0), Ok(1), None[int](), Err[int]("e"), GoOpt(0, errors.New("e"))} {
+			 count := 0
+			 for _, b := range []bool{opt.IsNone(), opt.IsSomeStrict(), opt.IsError()} { if b { count++ } }
+			 if count != 1 { t.Fatalf("expected exactly one state for %v", opt.Comparable()) }
+		 }
+	 })
+
+	 t.Run("OrElseErr", func(t *testing.T) {
+		 fallback := func(err error, code uint32) Optional[string] {
+			 if code == 404 { return Ok("default") }
+			 return Errf[string]("fallback failed: %w", err)
+		 }
+		 if o := OrElseErr(CodeErr[string](404, "missing"), fallback); o.IsError() || o.Value != "default" { t.Fatalf("expected recovery, got %v", o) }
+		 if o := OrElseErr(CodeErr[string](500, "down"), fallback); !o.IsError() || o.Error.Error() != "fallback failed: down" { t.Fatalf("expected new error, got %v", o) }
+		 called := false
+		 f := func(error, uint32) Optional[string] { called = true; return Ok("x") }
+		 if o := OrElseErr(Ok("v"), f); called || o.Value != "v" { t.Fatalf("f must not run on value") }
+		 if o := OrElseErr(None[string](), f); called || !o.IsNone() { t.Fatalf("f must not run on none") }
+	 })
+
+	 t.Run("Nothing", func(t *testing.T) {
+		 if Nothing[int]() != None[int]() { t.Fatalf("Nothing must equal None") }
+		 if !Nothing[string]().IsNothing() { t.Fatalf("Nothing must be nothing") }
+		 if Err[int]("e").IsNothing() || Ok(0).IsNothing() { t.Fatalf("errors and values must not be nothing") }
+	 })
+
+	 t.Run("Rust-style aliases", func(t *testing.T) {
+		 if !Ok(1).IsOk() || Ok(1).IsErr() { t.Fatalf("Ok must be ok") }
+		 if !None[int]().IsOk() { t.Fatalf("None must be ok") }
+		 opt := Err[int]("boom")
+		 if opt.IsOk() || !opt.IsErr() { t.Fatalf("Err must be err") }
+		 if opt.UnwrapErr().Error() != "boom" { t.Fatalf("unexpected error %v", opt.UnwrapErr()) }
+		 if r := mustPanic(t, func(){ Ok(1).UnwrapErr() }); !strings.Contains(fmt.Sprint(r), "UnwrapErr called on Optional without error") { t.Fatalf("unexpected panic value %v", r) }
+		 if r := mustPanic(t, func(){ None[int]().UnwrapErr() }); r == nil { t.Fatalf("expected panic for none") }
+	 })
+
+	 t.Run("AndThen", func(t *testing.T) {
+		 half := func(i int) Optional[int] {
+			 if i%2 != 0 { return CodeErr[int](2, "odd") }
+			 return Ok(i / 2)
+		 }
+		 if o := AndThen(Ok(8), half); o.Value != 4 { t.Fatalf("expected 4, got %v", o) }
+		 if o := AndThen(Ok(3), half); o.ErrorCode != 2 { t.Fatalf("expected f error, got %v", o) }
+		 if o := AndThen(CodeErr[int](9, "e"), half); o.ErrorCode != 9 { t.Fatalf("expected forwarded error, got %v", o) }
+		 if o := AndThen(None[int](), half); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+	 })
+
+	 t.Run("Bind monad laws", func(t *testing.T) {
+		 f := func(i int) Optional[string] {
+			 if i < 0 { return CodeErr[string](1, "negative") }
+			 if i == 0 { return None[string]() }
+			 return Ok(strconv.Itoa(i))
+		 }
+		 g := func(s string) Optional[int] {
+			 if len(s) > 1 { return Err[int]("too long") }
+			 return Ok(len(s))
+		 }
+		 values := []int{-1, 0, 1, 42}
+		 monads := []Optional[int]{Ok(-1), Ok(0), Ok(7), Ok(42), None[int](), CodeErr[int](5, "e")}
+		 for _, a := range values {
+			 if Bind(Ok(a), f).Comparable() != f(a).Comparable() { t.Fatalf("left identity violated for %d", a) }
+		 }
+		 for _, m := range monads {
+			 if Bind(m, Ok[int]).Comparable() != m.Comparable() { t.Fatalf("right identity violated for %v", m.Comparable()) }
+			 left := Bind(Bind(m, f), g)
+			 right := Bind(m, func(x int) Optional[int] { return Bind(f(x), g) })
+			 if left.Comparable() != right.Comparable() { t.Fatalf("associativity violated for %v: %v != %v", m.Comparable(), left.Comparable(), right.Comparable()) }
+		 }
+	 })
+
+	 t.Run("Make", func(t *testing.T) {
+		 cause := errors.New("e")
+		 for _, present := range []bool{false, true} {
+			 for _, err := range []error{nil, cause} {
+				 for _, code := range []uint32{0, 8} {
+					 opt := Make(0, present, err, code)
+					 switch {
+					 case err != nil || code != 0:
+						 if !opt.IsError() || opt.ErrorCode != code { t.Fatalf("present=%v err=%v code=%d: expected error, got %v", present, err, code, opt.Comparable()) }
+						 if err != nil && opt.Error != cause { t.Fatalf("expected error to be kept") }
+						 if opt.present != present { t.Fatalf("expected best-effort value presence %v", present) }
+					 case present:
+						 if !opt.IsSomeStrict() { t.Fatalf("expected present zero value, got %v", opt.Comparable()) }
+					 default:
+						 if !opt.IsNone() { t.Fatalf("expected none, got %v", opt.Comparable()) }
+					 }
+				 }
+			 }
+		 }
+		 if msg := Make(0, false, nil, 8).Error.Error(); msg != "error code 8" { t.Fatalf("unexpected generic message %q", msg) }
+	 })
+
+	 t.Run("ThenIfCode", func(t *testing.T) {
+		 useDefault := func(error) Optional[string] { return Ok("default") }
+		 if o := CodeErr[string](404, "missing").ThenIfCode(404, useDefault); o.Value != "default" || o.IsError() { t.Fatalf("expected recovery on matching code, got %v", o) }
+		 if o := CodeErr[string](500, "down").ThenIfCode(404, useDefault); o.ErrorCode != 500 { t.Fatalf("expected passthrough on other code, got %v", o) }
+		 if o := Ok("v").ThenIfCode(0, useDefault); o.Value != "v" { t.Fatalf("expected value passthrough, got %v", o) }
+		 chained := CodeErr[string](503, "busy").
+			 ThenIfCode(404, useDefault).
+			 ThenIfCode(503, func(err error) Optional[string] { return Ok("retried after " + err.Error()) })
+		 if chained.Value != "retried after busy" { t.Fatalf("unexpected chained result %v", chained) }
+	 })
+
+	 t.Run("OkNonEmpty", func(t *testing.T) {
+		 if !OkNonEmpty("").IsNone() || !OkNonEmpty([]int{}).IsNone() || !OkNonEmpty[[]int](nil).IsNone() { t.Fatalf("empty strings and slices must be none") }
+		 if !OkNonEmpty(map[string]int{}).IsNone() || !OkNonEmpty[*int](nil).IsNone() || !OkNonEmpty[any](nil).IsNone() { t.Fatalf("empty maps and nil pointers/interfaces must be none") }
+		 if o := OkNonEmpty("x"); o.Value != "x" { t.Fatalf("expected x, got %v", o) }
+		 if o := OkNonEmpty([]int{0}); len(o.Value) != 1 { t.Fatalf("expected non-empty slice, got %v", o) }
+		 if o := OkNonEmpty(0); !o.IsSomeStrict() { t.Fatalf("numbers are never empty") }
+		 if o := OkNonEmpty[any](""); o.IsNone() { t.Fatalf("non-nil interface holding an empty string is not empty") }
+	 })
+
+	 t.Run("KeyBy", func(t *testing.T) {
+		 type user struct{ ID int; Name string }
+		 keyed := KeyBy(Ok(user{ID: 7, Name: "ada"}), func(u user) int { return u.ID })
+		 if keyed.Value.First != 7 || keyed.Value.Second.Name != "ada" { t.Fatalf("unexpected pair %+v", keyed.Value) }
+		 called := false
+		 f := func(u user) int { called = true; return u.ID }
+		 if o := KeyBy(CodeErr[user](4, "e"), f); called || o.ErrorCode != 4 { t.Fatalf("expected forwarded error without calling f") }
+		 if o := KeyBy(None[user](), f); called || !o.IsNone() { t.Fatalf("expected none without calling f") }
+	 })
+
+	 t.Run("UnwrapIgnoringError", func(t *testing.T) {
+		 if v := GoOpt(12, errors.New("partial read")).UnwrapIgnoringError(); v != 12 { t.Fatalf("expected best-effort 12, got %d", v) }
+		 if v := Ok(5).UnwrapIgnoringError(); v != 5 { t.Fatalf("expected 5, got %d", v) }
+		 if v := Err[int]("e").UnwrapIgnoringError(); v != 0 { t.Fatalf("expected zero value, got %d", v) }
+	 })
+
+	 t.Run("RegisterErrorType", func(t *testing.T) {
+		 type status struct{ Code uint32; Message string }
+		 RegisterErrorType(reflect.TypeOf(0), func(err any) (uint32, error) { return 0, fmt.Errorf("int error %d", err) })
+		 RegisterErrorType(reflect.TypeOf(status{}), func(err any) (uint32, error) {
+			 s := err.(status)
+			 return s.Code, errors.New(s.Message)
+		 })
+		 defer RegisterErrorType(reflect.TypeOf(0), nil)
+		 defer RegisterErrorType(reflect.TypeOf(status{}), nil)
+
+		 intErr := CodeErr[string](12, 42)
+		 if intErr.ErrorCode != 12 || intErr.Error.Error() != "int error 42" { t.Fatalf("unexpected int conversion %v (%d)", intErr, intErr.ErrorCode) }
+		 statusErr := Err[string](status{Code: 503, Message: "unavailable"})
+		 if statusErr.ErrorCode != 503 || statusErr.Error.Error() != "unavailable" { t.Fatalf("unexpected status conversion %v (%d)", statusErr, statusErr.ErrorCode) }
+
+		 prevUnknown := unknownErrorHandler
+		 defer func(){ unknownErrorHandler = prevUnknown }()
+		 SetUnknownErrorHandler(func(code uint32, err any) (uint32, error) { return 1, errors.New("unknown") })
+		 if o := Err[string](3.5); o.Error.Error() != "unknown" { t.Fatalf("unregistered type must fall back to unknown handler, got %v", o) }
+		 if o := Err[string](7); o.Error.Error() != "int error 7" { t.Fatalf("registry must be consulted first, got %v", o) }
+	 })
+
+	 t.Run("Pluck", func(t *testing.T) {
+		 type user struct{ Name string; Email Optional[string] }
+		 u := Ok(user{Name: "ada", Email: None[string]()})
+		 if o := Pluck(u, func(u user) string { return u.Name }); o.Value != "ada" || !o.IsSomeStrict() { t.Fatalf("expected ada, got %v", o) }
+		 if o := PluckOpt(u, func(u user) Optional[string] { return u.Email }); !o.IsNone() { t.Fatalf("expected none for empty field, got %v", o) }
+		 if o := Pluck(CodeErr[user](4, "e"), func(u user) string { return u.Name }); o.ErrorCode != 4 { t.Fatalf("expected forwarded error, got %v", o) }
+		 if o := PluckOpt(None[user](), func(u user) Optional[string] { t.Fatalf("get must not run on none"); return u.Email }); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+	 })
+
+	 t.Run("UnwrapOrCtx", func(t *testing.T) {
+		 type localeKey struct{}
+		 ctx := context.WithValue(context.Background(), localeKey{}, "de")
+		 locale := func(ctx context.Context) string { return ctx.Value(localeKey{}).(string) }
+		 if v := Ok("").UnwrapOrCtx(ctx, func(context.Context) string { t.Fatalf("f must not run for a present zero value"); return "" }); v != "" { t.Fatalf("expected empty string, got %q", v) }
+		 if v := None[string]().UnwrapOrCtx(ctx, locale); v != "de" { t.Fatalf("expected default from context, got %q", v) }
+		 if v := Err[string]("e").UnwrapOrCtx(ctx, locale); v != "de" { t.Fatalf("expected default from context on error, got %q", v) }
+	 })
+
+	 t.Run("AsChan", func(t *testing.T) {
+		 ch := CodeErr[int](3, "e").AsChan()
+		 if o, ok := <-ch; !ok || o.ErrorCode != 3 { t.Fatalf("expected the Optional, got %v (ok=%v)", o, ok) }
+		 if _, ok := <-ch; ok { t.Fatalf("expected channel to be closed after one receive") }
+		 select {
+		 case o := <-Ok(7).AsChan():
+			 if o.Value != 7 { t.Fatalf("expected 7, got %v", o) }
+		 default:
+			 t.Fatalf("expected channel to be ready")
+		 }
+	 })
+
+	 t.Run("Finally", func(t *testing.T) {
+		 var oks, errs int
+		 onOk := func(int) { oks++ }
+		 onErr := func(error, uint32) { errs++ }
+		 if o := Ok(0).Finally(onOk, onErr); o != Ok(0) || oks != 1 || errs != 0 { t.Fatalf("expected only onOk, got %d/%d and %v", oks, errs, o) }
+		 e := CodeErr[int](5, "e")
+		 if o := e.Finally(onOk, onErr); o.ErrorCode != 5 || o.Error != e.Error || oks != 1 || errs != 1 { t.Fatalf("expected only onErr, got %d/%d and %v", oks, errs, o) }
+		 if o := None[int]().Finally(onOk, onErr); !o.IsNone() || oks != 1 || errs != 1 { t.Fatalf("expected no callback for none, got %d/%d", oks, errs) }
+		 Ok(1).Finally(nil, nil)
+	 })
+
+	 t.Run("SetStrictCodes", func(t *testing.T) {
+		 SetStrictCodes(true)
+		 defer SetStrictCodes(false)
+		 if o := CodeErr[int](PANIC_CODE, "oops"); o.ErrorCode != ARGUMENT_ERROR_CODE || !strings.Contains(o.Error.Error(), "reserved") || !strings.Contains(o.Error.Error(), "oops") { t.Fatalf("expected configuration error, got %v (%d)", o, o.ErrorCode) }
+		 if o := CodeErr[int](CONVERSION_ERROR_CODE, "e"); o.ErrorCode != ARGUMENT_ERROR_CODE { t.Fatalf("expected reserved code to be rejected, got %d", o.ErrorCode) }
+		 if o := CodeErr[int](RESERVED_CODE_MIN - 1, "e"); o.ErrorCode != RESERVED_CODE_MIN - 1 { t.Fatalf("expected highest application code to pass, got %d", o.ErrorCode) }
+		 if o := ConvertNumeric[uint8](Ok(300)); o.ErrorCode != CONVERSION_ERROR_CODE { t.Fatalf("package codes must not be affected, got %d", o.ErrorCode) }
+		 SetCastPanics(false)
+		 defer SetCastPanics(true)
+		 if o := Cast[string](Ok(1)); o.ErrorCode != CAST_ERROR_CODE { t.Fatalf("package codes must not be affected, got %d", o.ErrorCode) }
+		 notFound := MapLookupThen(map[string]int{}, "missing", Ok[int])
+		 if o := notFound.Rehandle(); o.ErrorCode != NOT_FOUND_ERROR_CODE || o.Error != notFound.Error { t.Fatalf("Rehandle must keep a reserved code, got %v (%d)", o, o.ErrorCode) }
+	 })
+
+	 t.Run("RemapCode", func(t *testing.T) {
+		 table := map[uint32]uint32{1001: 404, 1002: 409}
+		 if o := CodeErr[int](1001, "missing").RemapCode(table); o.ErrorCode != 404 || o.Error.Error() != "missing" { t.Fatalf("expected 404 with original error, got %v (%d)", o, o.ErrorCode) }
+		 if o := CodeErr[int](1003, "other").RemapCode(table); o.ErrorCode != 1003 { t.Fatalf("expected unmapped code to pass, got %d", o.ErrorCode) }
+		 if o := Ok(1).RemapCode(table); o != Ok(1) { t.Fatalf("expected value unchanged, got %v", o) }
+	 })
+
+	 t.Run("Tee", func(t *testing.T) {
+		 var seen []Optional[int]
+		 audit := func(o Optional[int]) { seen = append(seen, o); o.Value = 99; o.ErrorCode = 1 }
+		 if o := Ok(1).Tee(audit); o != Ok(1) { t.Fatalf("sink must not change the result, got %v", o) }
+		 if o := CodeErr[int](3, "e").Tee(audit); o.ErrorCode != 3 { t.Fatalf("expected error to pass, got %v", o) }
+		 if len(seen) != 2 || seen[0].Value != 1 || seen[1].ErrorCode != 3 { t.Fatalf("expected sink to see value and error, got %v", seen) }
+	 })
+
+	 t.Run("AsError", func(t *testing.T) {
+		 cause := errors.New("not found")
+		 err := CodeErrf[int](404, "lookup: %w", cause).AsError()
+		 var optErr *OptionalError
+		 if !errors.As(err, &optErr) || optErr.ErrorCode != 404 { t.Fatalf("expected code 404 via errors.As, got %v", err) }
+		 if !errors.Is(err, cause) || err.Error() != "lookup: not found" { t.Fatalf("expected wrapped cause, got %v", err) }
+		 wrapped := fmt.Errorf("handler: %w", err)
+		 if o := Err[int](wrapped); o.ErrorCode != 404 { t.Fatalf("expected CodeErr to pick up the code, got %d", o.ErrorCode) }
+		 if err := Ok(1).AsError(); err != nil { t.Fatalf("expected nil error for a value, got %v", err) }
+	 })
+
+	 t.Run("CompareOpt", func(t *testing.T) {
+		 opts := []Optional[int]{CodeErr[int](5, "b"), Ok(3), None[int](), CodeErr[int](2, "z"), Ok(-1), CodeErr[int](5, "a"), None[int](), Ok(0)}
+		 slices.SortFunc(opts, CompareOpt[int])
+		 want := []string{"none", "none", "-1", "0", "3", "z", "a", "b"}
+		 for i, o := range opts {
+			 got := o.String()
+			 if o.IsNone() { got = "none" }
+			 if got != want[i] { t.Fatalf("unexpected order at %d: got %s, want %s (%v)", i, got, want[i], Dump(opts)) }
+		 }
+		 if CompareOpt(Ok(1), Ok(1)) != 0 || CompareOpt(CodeErr[int](1, "e"), CodeErr[int](1, "e")) != 0 { t.Fatalf("expected equal Optionals to compare as 0") }
+	 })
+
+	 t.Run("MapLookupThen", func(t *testing.T) {
+		 cfg := map[string]string{"port": "8080", "host": "localhost"}
+		 parse := func(s string) Optional[int] { return GoOpt(strconv.Atoi(s)) }
+		 if o := MapLookupThen(cfg, "port", parse); o.Value != 8080 || o.IsError() { t.Fatalf("expected 8080, got %v", o) }
+		 if o := MapLookupThen(cfg, "timeout", parse); o.ErrorCode != NOT_FOUND_ERROR_CODE || !strings.Contains(o.Error.Error(), "timeout") { t.Fatalf("expected not found error, got %v (%d)", o, o.ErrorCode) }
+		 if o := MapLookupThen(cfg, "host", parse); !o.IsError() || o.ErrorCode == NOT_FOUND_ERROR_CODE { t.Fatalf("expected error from f, got %v (%d)", o, o.ErrorCode) }
+	 })
+
+	 t.Run("SetErrorInterning", func(t *testing.T) {
+		 if Err[int]("same").Error == Err[int]("same").Error { t.Fatalf("expected distinct errors without interning") }
+		 SetErrorInterning(true)
+		 defer SetErrorInterning(false)
+		 a, b := Err[int]("same"), CodeErr[string](3, "same")
+		 if a.Error != b.Error { t.Fatalf("expected interned errors to be identical") }
+		 if Err[int]("other").Error == a.Error { t.Fatalf("expected different messages to stay distinct") }
+	 })
+
+	 t.Run("MapToInt", func(t *testing.T) {
+		 const notFound, unknown = 5, 2
+		 table := map[uint32]int{1001: notFound}
+		 if c := CodeErr[int](1001, "e").MapToInt(table, unknown); c != notFound { t.Fatalf("expected %d, got %d", notFound, c) }
+		 if c := CodeErr[int](1002, "e").MapToInt(table, unknown); c != unknown { t.Fatalf("expected fallback %d, got %d", unknown, c) }
+		 if c := Err[int]("e").MapToInt(table, unknown); c != unknown { t.Fatalf("expected fallback for error without code, got %d", c) }
+		 if c := Ok(1).MapToInt(table, unknown); c != 0 { t.Fatalf("expected 0 for a value, got %d", c) }
+	 })
+
+	 t.Run("Catch", func(t *testing.T) {
+		 if v, err := Ok(2).Catch(); v != 2 || err != nil { t.Fatalf("expected 2, got %v %v", v, err) }
+		 if v, code, err := Ok(2).CatchCode(); v != 2 || code != 0 || err != nil { t.Fatalf("expected 2 without code, got %v %d %v", v, code, err) }
+		 if _, code, err := CodeErr[int](7, "e").CatchCode(); code != 7 || err == nil { t.Fatalf("expected code 7, got %d %v", code, err) }
+	 })
+
+	 t.Run("Unzip", func(t *testing.T) {
+		 a, b := Unzip(Ok(Pair[string, int]{First: "", Second: 2}))
+		 if !a.IsSomeStrict() || a.Value != "" || b.Value != 2 { t.Fatalf("expected both components, got %v %v", a, b) }
+		 a, b = Unzip(CodeErr[Pair[string, int]](6, "e"))
+		 if a.ErrorCode != 6 || b.ErrorCode != 6 || a.Error == nil || b.Error == nil { t.Fatalf("expected error in both results, got %v %v", a, b) }
+		 a, b = Unzip(None[Pair[string, int]]())
+		 if !a.IsNone() || !b.IsNone() { t.Fatalf("expected two empty results, got %v %v", a, b) }
+	 })
+
+	 t.Run("Invert", func(t *testing.T) {
+		 noneOk := func() error { return nil }
+		 if o := Invert(Ok("taken"), noneOk); !o.IsError() || !strings.Contains(o.Error.Error(), "taken") { t.Fatalf("expected error for a value, got %v", o) }
+		 if o := Invert(CodeErr[string](4, "not found"), noneOk); !o.IsSomeStrict() { t.Fatalf("expected OkVoid for an error, got %v", o) }
+		 if o := Invert(None[string](), noneOk); !o.IsSomeStrict() { t.Fatalf("expected OkVoid for none, got %v", o) }
+		 if o := Invert(None[string](), nil); !o.IsSomeStrict() { t.Fatalf("expected OkVoid for none without onNone, got %v", o) }
+		 if o := Invert(None[string](), func() error { return errors.New("no result") }); o.Error == nil || o.Error.Error() != "no result" { t.Fatalf("expected error from onNone, got %v", o) }
+	 })
+
+	 t.Run("SetUnwrapObserver", func(t *testing.T) {
+		 var observed []uint32
+		 SetUnwrapObserver(func(err error, code uint32) { observed = append(observed, code) })
+		 defer SetUnwrapObserver(nil)
+		 Ok(1).Unwrap()
+		 func() {
+			 defer func() { recover() }()
+			 CodeErr[int](9, "e").Unwrap()
+		 }()
+		 if len(observed) != 1 || observed[0] != 9 { t.Fatalf("expected one observation with code 9, got %v", observed) }
+	 })
+
+	 t.Run("SwitchCode", func(t *testing.T) {
+		 cases := map[uint32]func(error) Optional[int]{
+			 1: func(error) Optional[int] { return Ok(1) },
+			 2: func(err error) Optional[int] { return CodeErrf[int](20, "fatal: %w", err) },
+		 }
+		 dflt := func(err error, code uint32) Optional[int] { return Ok(int(code) * 100) }
+		 if o := CodeErr[int](1, "e").SwitchCode(cases, dflt); o != Ok(1) { t.Fatalf("expected case 1, got %v", o) }
+		 if o := CodeErr[int](2, "e").SwitchCode(cases, dflt); o.ErrorCode != 20 || o.Error.Error() != "fatal: e" { t.Fatalf("expected case 2, got %v", o) }
+		 if o := CodeErr[int](3, "e").SwitchCode(cases, dflt); o.Value != 300 { t.Fatalf("expected default, got %v", o) }
+		 if o := CodeErr[int](3, "e").SwitchCode(cases, nil); o.ErrorCode != 3 { t.Fatalf("expected error unchanged with nil default, got %v", o) }
+		 if o := Ok(7).SwitchCode(cases, dflt); o != Ok(7) { t.Fatalf("expected value unchanged, got %v", o) }
+	 })
+
+	 t.Run("Any and FromAny", func(t *testing.T) {
+		 mixed := []Optional[any]{Ok(0).Any(), Ok("s").Any(), CodeErr[float64](5, "e").Any(), None[int]().Any()}
+		 if !mixed[0].IsSomeStrict() || mixed[0].Value != 0 { t.Fatalf("expected boxed present zero value, got %v", mixed[0]) }
+		 if mixed[2].ErrorCode != 5 || mixed[2].Error.Error() != "e" { t.Fatalf("expected boxed error, got %v", mixed[2]) }
+		 if !mixed[3].IsNone() || mixed[3].Value != nil { t.Fatalf("expected boxed none, got %v", mixed[3]) }
+		 if o := FromAny[int](mixed[0]); !o.IsSomeStrict() || o.Value != 0 { t.Fatalf("expected unboxed 0, got %v", o) }
+		 if o := FromAny[string](mixed[1]); o.Value != "s" { t.Fatalf("expected unboxed s, got %v", o) }
+		 if o := FromAny[int](mixed[1]); o.ErrorCode != CAST_ERROR_CODE { t.Fatalf("expected CAST_ERROR_CODE for a mismatch, got %v (%d)", o, o.ErrorCode) }
+		 if o := FromAny[float64](mixed[2]); o.ErrorCode != 5 { t.Fatalf("expected forwarded error, got %v", o) }
+		 if o := FromAny[int](mixed[3]); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+	 })
+
+	 t.Run("UnwrapOrPanic", func(t *testing.T) {
+		 recovered := func(o Optional[int]) (r any) {
+			 defer func() { r = recover() }()
+			 o.UnwrapOrPanic()
+			 return nil
+		 }
+		 cause := errors.New("user not found")
+		 r := recovered(CodeErr[int](404, cause))
+		 if err, ok := r.(error); !ok || err.Error() != "optional failed [code 404]: user not found" || !errors.Is(err, cause) { t.Fatalf("unexpected panic value %v", r) }
+		 if r := recovered(Err[int]("plain")); fmt.Sprint(r) != "optional failed [code 0]: plain" { t.Fatalf("unexpected panic value %v", r) }
+		 if r := recovered(Make(0, false, nil, 7)); fmt.Sprint(r) != "optional failed [code 7]: error code 7" { t.Fatalf("unexpected panic value %v", r) }
+		 if v := Ok(3).UnwrapOrPanic(); v != 3 { t.Fatalf("expected 3, got %d", v) }
+	 })
+}
+
+func BenchmarkErr(b *testing.B) {
+	for _, intern := range []bool{false, true} {
+		b.Run(fmt.Sprintf("interning=%v", intern), func(b *testing.B) {
+			SetErrorInterning(intern)
+			defer SetErrorInterning(false)
+			b.ReportAllocs()
+			for b.Loop() {
+				Err[int]("record rejected")
+			}
+		})
+	}
 }
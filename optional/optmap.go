@@ -0,0 +1,69 @@
+package optional
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+//*********************************************************************************
+//                                  type OptMap
+//*********************************************************************************
+
+// Map of possibly failed computations, e.g. a cache of lookups.
+// Not safe for concurrent use, guard it with a mutex if shared between goroutines.
+type OptMap[K comparable, V any] map[K]Optional[V]
+
+// Get the stored Optional for k, or compute it with f and store it on a miss.
+// Errors are stored as well, delete the key to compute it again.
+func (m OptMap[K, V]) GetOrCompute(k K, f func() Optional[V]) Optional[V] {
+	if o, ok := m[k]; ok {
+		return o
+	}
+	o := f()
+	m[k] = o
+	return o
+}
+
+// Get the values of all entries holding a value without error.
+func (m OptMap[K, V]) Presents() map[K]V {
+	out := make(map[K]V, len(m))
+	for k, o := range m {
+		if !o.IsError() && o.present {
+			out[k] = o.Value
+		}
+	}
+	return out
+}
+
+//*********************************************************************************
+//                              Map Helpers
+//*********************************************************************************
+
+// Collect the values of m into a plain map, or return an error if any entry is an error.
+// Empty entries are skipped. Map iteration order is random, so with several errors it is
+// unspecified which one is returned, use CollectMapSorted for a deterministic choice.
+func CollectMap[K comparable, V any](m map[K]Optional[V]) Optional[map[K]V] {
+	out := make(map[K]V, len(m))
+	for _, o := range m {
+		if o.IsError() {
+			return Forward[map[K]V](o)
+		}
+	}
+	for k, o := range m {
+		if o.present {
+			out[k] = o.Value
+		}
+	}
+	return Ok(out)
+}
+
+// Like CollectMap, but returns the error of the smallest failing key.
+func CollectMapSorted[K cmp.Ordered, V any](m map[K]Optional[V]) Optional[map[K]V] {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		if o := m[k]; o.IsError() {
+			return Forward[map[K]V](o)
+		}
+	}
+	return CollectMap(m)
+}
@@ -0,0 +1,40 @@
+package optional
+
+import "testing"
+
+func TestOptMap(t *testing.T) {
+	t.Run("GetOrCompute computes on miss and hits afterwards", func(t *testing.T) {
+		m := OptMap[string, int]{}
+		calls := 0
+		compute := func() Optional[int] { calls++; return Ok(7) }
+		if o := m.GetOrCompute("a", compute); o.Value != 7 { t.Fatalf("expected 7, got %v", o) }
+		if o := m.GetOrCompute("a", compute); o.Value != 7 || calls != 1 { t.Fatalf("expected cached 7 after one call, got %v after %d calls", o, calls) }
+		if _, ok := m["a"]; !ok { t.Fatalf("expected computed value to be stored") }
+	})
+
+	t.Run("GetOrCompute stores errors", func(t *testing.T) {
+		m := OptMap[string, int]{}
+		m.GetOrCompute("bad", func() Optional[int] { return CodeErr[int](3, "e") })
+		if o := m.GetOrCompute("bad", func() Optional[int] { return Ok(1) }); o.ErrorCode != 3 { t.Fatalf("expected stored error, got %v", o) }
+	})
+
+	t.Run("Presents filters entries", func(t *testing.T) {
+		m := OptMap[string, int]{"ok": Ok(1), "zero": Ok(0), "err": Err[int]("e"), "none": None[int]()}
+		p := m.Presents()
+		if len(p) != 2 || p["ok"] != 1 { t.Fatalf("unexpected presents %v", p) }
+		if _, ok := p["zero"]; !ok { t.Fatalf("present zero value must be kept") }
+	})
+
+	t.Run("CollectMap", func(t *testing.T) {
+		opt := CollectMap(map[string]Optional[int]{"a": Ok(1), "zero": Ok(0), "none": None[int]()})
+		if !opt.IsSomeStrict() || len(opt.Value) != 2 || opt.Value["a"] != 1 { t.Fatalf("unexpected map %v", opt) }
+		if opt := CollectMap(OptMap[string, int]{"a": Ok(1), "b": CodeErr[int](4, "e")}); opt.ErrorCode != 4 { t.Fatalf("expected error, got %v", opt) }
+	})
+
+	t.Run("CollectMapSorted picks the smallest failing key", func(t *testing.T) {
+		m := map[string]Optional[int]{"c": CodeErr[int](3, "c"), "a": Ok(1), "b": CodeErr[int](2, "b"), "d": CodeErr[int](4, "d")}
+		for range 10 {
+			if opt := CollectMapSorted(m); opt.ErrorCode != 2 { t.Fatalf("expected error of key b, got %v", opt) }
+		}
+	})
+}
@@ -0,0 +1,36 @@
+// Test helpers for code working with optional.Optional.
+package opttest
+
+import (
+	"testing"
+
+	"github.com/dontenwill/go-modules/optional"
+)
+
+//*********************************************************************************
+//                                  Assertions
+//*********************************************************************************
+
+// Fail the test unless o holds a present value without error, then return the value.
+// A present zero value counts as success.
+func AssertOk[T any](t testing.TB, o optional.Optional[T]) T {
+	t.Helper()
+	switch {
+	case o.IsError():
+		t.Fatalf("expected value, got error %q (code %d)", o.Error, o.ErrorCode)
+	case o.IsNone():
+		t.Fatalf("expected value, got none")
+	}
+	return o.Value
+}
+
+// Fail the test unless o holds an error with the given code.
+func AssertErrCode[T any](t testing.TB, o optional.Optional[T], code uint32) {
+	t.Helper()
+	switch {
+	case !o.IsError():
+		t.Fatalf("expected error with code %d, got value %v", code, o)
+	case o.ErrorCode != code:
+		t.Fatalf("expected error code %d, got %d (%q)", code, o.ErrorCode, o.Error)
+	}
+}
@@ -0,0 +1,49 @@
+package opttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dontenwill/go-modules/optional"
+)
+
+// Records the first failure instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	if r.failure == "" {
+		r.failure = fmt.Sprintf(format, args...)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	t.Run("AssertOk returns the value", func(t *testing.T) {
+		if v := AssertOk(t, optional.Ok(0)); v != 0 { t.Fatalf("expected 0, got %d", v) }
+		if v := AssertOk(t, optional.Ok("a")); v != "a" { t.Fatalf("expected a, got %q", v) }
+	})
+
+	t.Run("AssertOk reports error and code", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertOk(r, optional.CodeErr[int](7, "boom"))
+		if !strings.Contains(r.failure, "boom") || !strings.Contains(r.failure, "7") { t.Fatalf("unexpected message %q", r.failure) }
+		r = &recorder{TB: t}
+		AssertOk(r, optional.None[int]())
+		if !strings.Contains(r.failure, "none") { t.Fatalf("unexpected message %q", r.failure) }
+	})
+
+	t.Run("AssertErrCode", func(t *testing.T) {
+		AssertErrCode(t, optional.CodeErr[int](7, "boom"), 7)
+		r := &recorder{TB: t}
+		AssertErrCode(r, optional.CodeErr[int](8, "boom"), 7)
+		if !strings.Contains(r.failure, "got 8") { t.Fatalf("unexpected message %q", r.failure) }
+		r = &recorder{TB: t}
+		AssertErrCode(r, optional.Ok(1), 7)
+		if !strings.Contains(r.failure, "got value 1") { t.Fatalf("unexpected message %q", r.failure) }
+	})
+}
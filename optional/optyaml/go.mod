@@ -0,0 +1,8 @@
+module github.com/dontenwill/go-modules/optional/optyaml
+
+go 1.24.6
+
+require (
+	github.com/dontenwill/go-modules/optional v0.0.0-20261015113139-e1178d5134ab
+	gopkg.in/yaml.v3 v3.0.1
+)
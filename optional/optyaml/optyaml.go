@@ -0,0 +1,80 @@
+// YAML encoding for optional.Optional, kept in its own module so the optional package does not depend on yaml.
+package optyaml
+
+import (
+	"errors"
+
+	"github.com/dontenwill/go-modules/optional"
+	"gopkg.in/yaml.v3"
+)
+
+//*********************************************************************************
+//                                 struct Optional
+//*********************************************************************************
+
+// An optional.Optional that encodes to and decodes from YAML (gopkg.in/yaml.v3).
+// Use it for struct fields in place of optional.Optional:
+//
+//	type config struct {
+//		Proxy optyaml.Optional[string] `yaml:"proxy"`
+//	}
+type Optional[T any] struct {
+	optional.Optional[T]
+}
+
+// Wrap o for YAML encoding.
+func Of[T any](o optional.Optional[T]) Optional[T] {
+	return Optional[T]{o}
+}
+
+// Error form of an Optional in YAML, tagged with ERROR_TAG.
+type yamlError struct {
+	Error string `yaml:"error"`
+	Code  uint32 `yaml:"code"`
+}
+
+// Tag that marks the error form, so a mapping value with the keys error and code stays a value.
+const ERROR_TAG = "!error"
+
+// Encode the Optional: the bare value, null if empty, or a mapping !error {error: message, code: n} on error.
+func (o Optional[T]) MarshalYAML() (any, error) {
+	switch {
+	case o.IsError():
+		e := yamlError{Code: o.ErrorCode}
+		if o.Error != nil {
+			e.Error = o.Error.Error()
+		}
+		var node yaml.Node
+		if err := node.Encode(e); err != nil {
+			return nil, err
+		}
+		node.Tag = ERROR_TAG
+		return &node, nil
+	case o.IsSomeStrict():
+		return o.Value, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Decode the Optional, the inverse of MarshalYAML. A null or missing field leaves an empty Optional.
+// Only a node tagged ERROR_TAG is decoded as error, the error handlers are not called again.
+func (o *Optional[T]) UnmarshalYAML(node *yaml.Node) error {
+	switch {
+	case node.Tag == ERROR_TAG:
+		var e yamlError
+		if err := node.Decode(&e); err != nil {
+			return err
+		}
+		o.Optional = optional.Optional[T]{Error: errors.New(e.Error), ErrorCode: e.Code}
+	case node.ShortTag() == "!!null":
+		o.Optional = optional.Optional[T]{}
+	default:
+		var value T
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		o.Optional = optional.Ok(value)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package optyaml
+
+import (
+	"testing"
+
+	"github.com/dontenwill/go-modules/optional"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML(t *testing.T) {
+	type config struct {
+		Name  Optional[string]            `yaml:"name"`
+		Port  Optional[int]               `yaml:"port"`
+		Proxy Optional[string]            `yaml:"proxy"`
+		Tags  Optional[map[string]string] `yaml:"tags"`
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		in := config{Name: Of(optional.Ok("api")), Port: Of(optional.CodeErr[int](17, "invalid port")), Tags: Of(optional.Ok(map[string]string{"error": "tag", "code": "1"}))}
+		data, err := yaml.Marshal(in)
+		if err != nil { t.Fatalf("unexpected error: %v", err) }
+		var out config
+		if err := yaml.Unmarshal(data, &out); err != nil { t.Fatalf("unexpected error: %v\n%s", err, data) }
+		if out.Name.IsError() || out.Name.Value != "api" { t.Fatalf("expected name api, got %v", out.Name) }
+		if out.Port.ErrorCode != 17 || out.Port.Error.Error() != "invalid port" { t.Fatalf("expected port error, got %v", out.Port) }
+		if !out.Proxy.IsNone() { t.Fatalf("expected proxy none, got %v", out.Proxy) }
+		if !out.Tags.IsSome() || out.Tags.Value["error"] != "tag" || out.Tags.Value["code"] != "1" { t.Fatalf("map with error and code keys must stay a value, got %v", out.Tags) }
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		data, err := yaml.Marshal(config{Name: Of(optional.Ok("")), Port: Of(optional.Err[int]("bad"))})
+		if err != nil { t.Fatalf("unexpected error: %v", err) }
+		want := "name: \"\"\nport: !error\n    error: bad\n    code: 0\nproxy: null\ntags: null\n"
+		if string(data) != want { t.Fatalf("unexpected yaml:\n%s\nwant:\n%s", data, want) }
+	})
+
+	t.Run("Decoding plain config", func(t *testing.T) {
+		var cfg config
+		if err := yaml.Unmarshal([]byte("name: web\nport: 8080\nproxy: null\ntags: {error: x, code: 3}\n"), &cfg); err != nil { t.Fatalf("unexpected error: %v", err) }
+		if cfg.Name.Value != "web" || cfg.Port.Value != 8080 || !cfg.Proxy.IsNone() { t.Fatalf("unexpected config: %+v", cfg) }
+		if !cfg.Tags.IsSome() || cfg.Tags.Value["code"] != "3" { t.Fatalf("expected tags value, got %v", cfg.Tags) }
+		if err := yaml.Unmarshal([]byte("port: http\n"), &cfg); err == nil { t.Fatalf("expected type error") }
+	})
+}
@@ -0,0 +1,36 @@
+package optional
+
+import "sync"
+
+//*********************************************************************************
+//                                  type Pool
+//*********************************************************************************
+
+// Reuses []Optional[T] buffers in hot paths to reduce allocations. The zero value is ready to use.
+// Reuse contract: after Put the caller must not touch the slice or any slice sharing its
+// backing array again, and values taken out of it must be copied before Put if they are kept.
+// Safe for concurrent use.
+type Pool[T any] struct {
+	pool sync.Pool // of *[]Optional[T]
+}
+
+// Get a buffer of length n with every element empty. Its capacity may be larger.
+func (p *Pool[T]) Get(n int) []Optional[T] {
+	if buf, ok := p.pool.Get().(*[]Optional[T]); ok && cap(*buf) >= n {
+		return (*buf)[:n]
+	}
+	return make([]Optional[T], n)
+}
+
+// Return a buffer obtained from Get. Its elements are cleared with Reset so that
+// stale values and errors neither leak into the next Get nor stay reachable for the GC.
+func (p *Pool[T]) Put(opts []Optional[T]) {
+	opts = opts[:cap(opts)]
+	p.Reset(opts)
+	p.pool.Put(&opts)
+}
+
+// Set every element of opts to an empty Optional.
+func (p *Pool[T]) Reset(opts []Optional[T]) {
+	clear(opts)
+}
@@ -0,0 +1,30 @@
+package optional
+
+import "testing"
+
+func TestPool(t *testing.T) {
+	t.Run("Get returns empty buffers", func(t *testing.T) {
+		var p Pool[string]
+		buf := p.Get(3)
+		if len(buf) != 3 { t.Fatalf("expected length 3, got %d", len(buf)) }
+		buf[0], buf[1], buf[2] = Ok("a"), CodeErr[string](4, "e"), Ok("")
+		p.Put(buf)
+		for range 10 {
+			reused := p.Get(2)
+			for i, o := range reused {
+				if !o.IsNone() || o.Value != "" { t.Fatalf("expected empty element at %d, got %v", i, o) }
+			}
+			p.Put(reused)
+		}
+		if buf := p.Get(100); len(buf) != 100 { t.Fatalf("expected length 100, got %d", len(buf)) }
+	})
+
+	t.Run("Reset clears values and errors", func(t *testing.T) {
+		var p Pool[int]
+		opts := []Optional[int]{Ok(1), CodeErr[int](2, "e"), Ok(0)}
+		p.Reset(opts)
+		for i, o := range opts {
+			if !o.IsNone() || o.Error != nil || o.ErrorCode != 0 { t.Fatalf("expected empty element at %d, got %v", i, o) }
+		}
+	})
+}
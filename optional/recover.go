@@ -0,0 +1,51 @@
+package optional
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+//*********************************************************************************
+//                                Panic Recovery
+//*********************************************************************************
+
+// Run a handler and convert a panic into an error with RECOVERED_PANIC_CODE, e.g. in HTTP middleware.
+// The error message contains the recovered value and the stack trace, a recovered error stays
+// reachable through errors.Is / errors.As. The hook set by SetPanicHook is called before the error is created.
+// Every panic is recovered, including escalations with PANIC_CODE raised inside h.
+func RecoverHandler[T any](h func() Optional[T]) (result Optional[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = recoveredErr[T](r, debug.Stack())
+		}
+	}()
+	return h()
+}
+
+// Convert a panic of the surrounding function into an error with RECOVERED_PANIC_CODE in result,
+// like RecoverHandler but without wrapping the body in a closure. Must be deferred directly:
+//
+//	func load() (result optional.Optional[Config]) {
+//		defer optional.Guard(&result)
+//		...
+//	}
+//
+// Without a panic, result is left unchanged.
+func Guard[T any](result *Optional[T]) {
+	if r := recover(); r != nil {
+		*result = recoveredErr[T](r, debug.Stack())
+	}
+}
+
+func recoveredErr[T any](r any, stack []byte) Optional[T] {
+	handlerMu.RLock()
+	hook := panicHook
+	handlerMu.RUnlock()
+	if hook != nil {
+		hook(r, stack)
+	}
+	if err, ok := r.(error); ok {
+		return codeErr[T](RECOVERED_PANIC_CODE, fmt.Errorf("panic: %w\n%s", err, stack))
+	}
+	return codeErr[T](RECOVERED_PANIC_CODE, fmt.Sprintf("panic: %v\n%s", r, stack))
+}
@@ -0,0 +1,45 @@
+package optional
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("RecoverHandler converts panic", func(t *testing.T) {
+		var hooked any
+		SetPanicHook(func(recovered any, stack []byte) { hooked = recovered })
+		defer SetPanicHook(nil)
+		opt := RecoverHandler(func() Optional[int] { panic("handler exploded") })
+		if opt.ErrorCode != RECOVERED_PANIC_CODE { t.Fatalf("expected RECOVERED_PANIC_CODE, got %d", opt.ErrorCode) }
+		msg := opt.Error.Error()
+		if !strings.HasPrefix(msg, "panic: handler exploded\n") { t.Fatalf("unexpected message %q", msg) }
+		if !strings.Contains(msg, "goroutine") || !strings.Contains(msg, "recover_test.go") { t.Fatalf("expected stack trace in message, got %q", msg) }
+		if hooked != "handler exploded" { t.Fatalf("expected hook to receive recovered value, got %v", hooked) }
+	})
+
+	t.Run("RecoverHandler keeps recovered error", func(t *testing.T) {
+		cause := errors.New("nil map")
+		opt := RecoverHandler(func() Optional[int] { panic(cause) })
+		if !errors.Is(opt.Error, cause) { t.Fatalf("expected recovered error to be wrapped") }
+	})
+
+	t.Run("RecoverHandler passes through", func(t *testing.T) {
+		if opt := RecoverHandler(func() Optional[int] { return Ok(3) }); opt.Value != 3 || opt.IsError() { t.Fatalf("expected 3, got %v", opt) }
+		if opt := RecoverHandler(func() Optional[int] { return CodeErr[int](4, "e") }); opt.ErrorCode != 4 { t.Fatalf("expected code 4, got %v", opt) }
+	})
+
+	t.Run("Guard converts panic of the surrounding function", func(t *testing.T) {
+		cause := errors.New("boom")
+		guarded := func(p any) (result Optional[int]) {
+			defer Guard(&result)
+			if p != nil { panic(p) }
+			return Ok(1)
+		}
+		if opt := guarded(nil); opt != Ok(1) { t.Fatalf("expected result unchanged, got %v", opt) }
+		if opt := guarded(cause); opt.ErrorCode != RECOVERED_PANIC_CODE || !errors.Is(opt.Error, cause) { t.Fatalf("expected wrapped error, got %v", opt) }
+		if opt := guarded("text"); !strings.HasPrefix(opt.Error.Error(), "panic: text\n") { t.Fatalf("unexpected message %q", opt.Error) }
+		if opt := guarded(42); opt.ErrorCode != RECOVERED_PANIC_CODE || !strings.HasPrefix(opt.Error.Error(), "panic: 42\n") { t.Fatalf("unexpected error %v", opt) }
+	})
+}
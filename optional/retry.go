@@ -0,0 +1,68 @@
+package optional
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+//*********************************************************************************
+//                                 Retry Policy
+//*********************************************************************************
+
+// Configures RetryWith. Delays grow exponentially: the n-th retry waits BaseDelay * 2^(n-1), up to MaxDelay,
+// scaled by a random factor in [1-Jitter, 1+Jitter] to spread retries of concurrent callers.
+type RetryPolicy struct {
+	MaxAttempts int           // attempts including the first one, 0 for no limit
+	BaseDelay   time.Duration // delay before the first retry
+	MaxDelay    time.Duration // limit of the growing delay before jitter, 0 for no limit
+	Jitter      float64       // relative jitter in [0, 1], 0 for exact delays, values outside are clamped
+	MaxElapsed  time.Duration // no retry is started if it would begin later than this after the first attempt, 0 for no limit
+
+	Now   func() time.Time    // clock, time.Now if nil
+	Sleep func(time.Duration) // time.Sleep if nil
+}
+
+// Call f until it returns a value or an empty Optional, or the policy gives up.
+// Returns the result of the last attempt, so on give-up the last error with its code.
+// A policy without MaxAttempts and MaxElapsed would never give up and returns an error with ARGUMENT_ERROR_CODE.
+func RetryWith[T any](p RetryPolicy, f func() Optional[T]) Optional[T] {
+	if p.MaxAttempts <= 0 && p.MaxElapsed <= 0 {
+		return codeErr[T](ARGUMENT_ERROR_CODE, fmt.Errorf("RetryWith: policy needs MaxAttempts or MaxElapsed"))
+	}
+	now, sleep := p.Now, p.Sleep
+	if now == nil {
+		now = time.Now
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	// without a MaxDelay the delay still stops growing before it and its jitter could overflow
+	maxDelay := time.Duration(math.MaxInt64 / 4)
+	if p.MaxDelay > 0 {
+		maxDelay = min(p.MaxDelay, maxDelay)
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	start := now()
+	delay := min(p.BaseDelay, maxDelay)
+	for attempt := 1; ; attempt++ {
+		result := f()
+		if !result.IsError() || attempt == p.MaxAttempts {
+			return result
+		}
+		wait := delay
+		if jitter > 0 {
+			wait = time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+		}
+		if p.MaxElapsed > 0 && now().Add(wait).Sub(start) > p.MaxElapsed {
+			return result
+		}
+		sleep(wait)
+		if delay < maxDelay/2 {
+			delay *= 2
+		} else {
+			delay = maxDelay
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package optional
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// Fake clock advanced by sleeping.
+type retryClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *retryClock) policy(p RetryPolicy) RetryPolicy {
+	p.Now = func() time.Time { return c.now }
+	p.Sleep = func(d time.Duration) { c.sleeps = append(c.sleeps, d); c.now = c.now.Add(d) }
+	return p
+}
+
+func TestRetry(t *testing.T) {
+	failing := func(calls *int) func() Optional[int] {
+		return func() Optional[int] { *calls++; return CodeErr[int](uint32(*calls), "unavailable") }
+	}
+
+	t.Run("RetryWith stops after MaxAttempts with backoff", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		opt := RetryWith(clock.policy(RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second}), failing(&calls))
+		if calls != 4 || opt.ErrorCode != 4 { t.Fatalf("expected 4 attempts returning the last error, got %d calls and %v", calls, opt) }
+		if !slices.Equal(clock.sleeps, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}) { t.Fatalf("unexpected delays %v", clock.sleeps) }
+	})
+
+	t.Run("RetryWith returns the first success", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		opt := RetryWith(clock.policy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}), func() Optional[int] {
+			calls++
+			if calls < 3 { return Err[int]("busy") }
+			return Ok(calls)
+		})
+		if opt.Value != 3 || opt.IsError() { t.Fatalf("expected success on third attempt, got %v", opt) }
+	})
+
+	t.Run("RetryWith truncates at MaxElapsed", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		opt := RetryWith(clock.policy(RetryPolicy{BaseDelay: time.Second, MaxElapsed: 10 * time.Second}), failing(&calls))
+		// attempts at 0s, 1s, 3s, 7s; the next would start at 15s
+		if calls != 4 || opt.ErrorCode != 4 { t.Fatalf("expected 4 attempts, got %d calls and %v", calls, opt) }
+		if clock.now.Sub(time.Time{}) != 7*time.Second { t.Fatalf("expected to give up at 7s, got %v", clock.now.Sub(time.Time{})) }
+	})
+
+	t.Run("RetryWith applies jitter", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 20, BaseDelay: time.Second, Jitter: 0.5}), failing(&calls))
+		for i, d := range clock.sleeps {
+			base := time.Second << i
+			if d < base/2 || d > base*3/2 { t.Fatalf("delay %d out of jitter range: %v", i, d) }
+		}
+	})
+
+	t.Run("RetryWith limits the delay", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 6, BaseDelay: time.Second, MaxDelay: 5 * time.Second}), failing(&calls))
+		if !slices.Equal(clock.sleeps, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}) { t.Fatalf("unexpected delays %v", clock.sleeps) }
+		clock = &retryClock{}
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 100, BaseDelay: time.Second}), failing(&calls))
+		for i, d := range clock.sleeps {
+			if d <= 0 || (i > 0 && d < clock.sleeps[i-1]) { t.Fatalf("delay %d overflowed: %v", i, d) }
+		}
+		clock = &retryClock{}
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 100, BaseDelay: time.Second, Jitter: 1}), failing(&calls))
+		for i, d := range clock.sleeps {
+			if d < 0 { t.Fatalf("delay %d overflowed with jitter: %v", i, d) }
+		}
+	})
+
+	t.Run("RetryWith clamps jitter", func(t *testing.T) {
+		clock := &retryClock{}
+		calls := 0
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 20, BaseDelay: time.Second, Jitter: 3}), failing(&calls))
+		for i, d := range clock.sleeps {
+			if d < 0 || d > (time.Second<<i)*2 { t.Fatalf("delay %d out of clamped jitter range: %v", i, d) }
+		}
+		clock = &retryClock{}
+		RetryWith(clock.policy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, Jitter: -1}), failing(&calls))
+		if !slices.Equal(clock.sleeps, []time.Duration{time.Second, 2 * time.Second}) { t.Fatalf("negative jitter must mean exact delays, got %v", clock.sleeps) }
+	})
+
+	t.Run("RetryWith rejects unbounded policies", func(t *testing.T) {
+		calls := 0
+		if opt := RetryWith(RetryPolicy{BaseDelay: time.Second}, failing(&calls)); opt.ErrorCode != ARGUMENT_ERROR_CODE || calls != 0 { t.Fatalf("expected ARGUMENT_ERROR_CODE without calls, got %v", opt) }
+	})
+}
@@ -0,0 +1,424 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+//*********************************************************************************
+//                           Batch Helpers ([]Optional)
+//*********************************************************************************
+
+// Fold the values of opts into an accumulator with a function that can fail.
+// Stops at the first error, either from an element of opts or returned by f.
+// Empty elements are skipped.
+func FoldM[T any, A any](opts []Optional[T], init A, f func(A, T) Optional[A]) Optional[A] {
+	acc := init
+	for _, o := range opts {
+		if o.IsError() {
+			return Forward[A](o)
+		}
+		if !o.present {
+			continue
+		}
+		next := f(acc, o.Value)
+		if next.IsError() {
+			return next
+		}
+		acc = next.Value
+	}
+	return Ok(acc)
+}
+
+// Split opts into consecutive chunks of at most size elements, the last chunk may be smaller.
+// The chunks share the backing array of opts.
+// An invalid size <= 0 returns nil, while valid sizes always return a non-nil result (empty for empty opts),
+// so nil unambiguously signals the invalid size. Use CollectChunks for an error with ARGUMENT_ERROR_CODE instead.
+func Chunk[T any](opts []Optional[T], size int) [][]Optional[T] {
+	if size <= 0 {
+		return nil
+	}
+	n := len(opts) / size
+	if len(opts)%size != 0 {
+		n++
+	}
+	chunks := make([][]Optional[T], 0, n)
+	for start := 0; start < len(opts); start += size {
+		end := min(start+size, len(opts))
+		chunks = append(chunks, opts[start:end:end])
+	}
+	return chunks
+}
+
+// Like Chunk, but yields the chunks lazily, e.g. to process a stream in batches.
+// Yields nothing if size <= 0.
+func ChunkSeq[T any](opts []Optional[T], size int) iter.Seq[[]Optional[T]] {
+	return func(yield func([]Optional[T]) bool) {
+		if size <= 0 {
+			return
+		}
+		for start := 0; start < len(opts); start += size {
+			end := min(start+size, len(opts))
+			if !yield(opts[start:end:end]) {
+				return
+			}
+		}
+	}
+}
+
+// Collect the values of opts into chunks of at most size values, stopping at the first error.
+// Empty elements are skipped, so only the last chunk may be smaller than size.
+// A size <= 0 returns an error with ARGUMENT_ERROR_CODE.
+func CollectChunks[T any](opts []Optional[T], size int) Optional[[][]T] {
+	if size <= 0 {
+		return codeErr[[][]T](ARGUMENT_ERROR_CODE, fmt.Errorf("CollectChunks: invalid chunk size %d", size))
+	}
+	chunks := [][]T{}
+	var current []T
+	for _, o := range opts {
+		if o.IsError() {
+			return Forward[[][]T](o)
+		}
+		if !o.present {
+			continue
+		}
+		current = append(current, o.Value)
+		if len(current) == size {
+			chunks = append(chunks, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return Ok(chunks)
+}
+
+// Render opts as a compact multi-line summary for logs and test failures, one line per element:
+//
+//	[0] ok: 42
+//	[1] err(123): boom
+//	[2] none
+//
+// Errors without a code are rendered as "err: message".
+func Dump[T any](opts []Optional[T]) string {
+	var sb strings.Builder
+	for i, o := range opts {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "[%d] %s", i, dumpOne(o))
+	}
+	return sb.String()
+}
+
+func dumpOne[T any](o Optional[T]) string {
+	switch {
+	case o.IsError():
+		s := "err"
+		if o.HasErrorCode() {
+			s += fmt.Sprintf("(%d)", o.ErrorCode)
+		}
+		if o.Error != nil {
+			s += ": " + o.Error.Error()
+		}
+		return s
+	case o.present:
+		return fmt.Sprintf("ok: %v", o.Value)
+	}
+	return "none"
+}
+
+// Describe the differences between two batches per index, one line each in the format of Dump,
+// e.g. "[2] error appeared: ok: 1 -> err(3): timeout". Returns "" if the batches are equal.
+// Errors are equal if code and message are equal, best-effort values of errors are ignored.
+func DiffBatches[T comparable](a, b []Optional[T]) string {
+	var lines []string
+	for i := range max(len(a), len(b)) {
+		switch {
+		case i >= len(a):
+			lines = append(lines, fmt.Sprintf("[%d] added: %s", i, dumpOne(b[i])))
+		case i >= len(b):
+			lines = append(lines, fmt.Sprintf("[%d] removed: %s", i, dumpOne(a[i])))
+		default:
+			if kind := diffKind(a[i], b[i]); kind != "" {
+				lines = append(lines, fmt.Sprintf("[%d] %s: %s -> %s", i, kind, dumpOne(a[i]), dumpOne(b[i])))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func diffKind[T comparable](a, b Optional[T]) string {
+	switch {
+	case a.IsError() && b.IsError():
+		if dumpOne(a) != dumpOne(b) {
+			return "error changed"
+		}
+	case b.IsError():
+		return "error appeared"
+	case a.IsError():
+		return "error disappeared"
+	case a.present != b.present:
+		return "state flipped"
+	case a.present && a.Value != b.Value:
+		return "value changed"
+	}
+	return ""
+}
+
+// Map every element of in with f and keep only the present values, errors and empty results are dropped.
+// Never stops early, f is called for every element.
+func FilterMap[T any, U any](in []T, f func(T) Optional[U]) []U {
+	out := []U{}
+	for _, v := range in {
+		if o := f(v); !o.IsError() && o.present {
+			out = append(out, o.Value)
+		}
+	}
+	return out
+}
+
+// Keep only the elements holding a value without error, including present zero values.
+func KeepSome[T any](opts []Optional[T]) []Optional[T] {
+	out := []Optional[T]{}
+	for _, o := range opts {
+		if !o.IsError() && o.present {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Keep all elements without error, i.e. values and empty elements.
+func DropErrors[T any](opts []Optional[T]) []Optional[T] {
+	out := []Optional[T]{}
+	for _, o := range opts {
+		if !o.IsError() {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// Count the elements holding a value, an error or nothing. ok+errc+none always equals len(opts).
+// A present zero value counts as ok, an error with a best-effort value (see GoOpt) as error.
+func Stats[T any](opts []Optional[T]) (ok, errc, none int) {
+	for _, o := range opts {
+		switch {
+		case o.IsError():
+			errc++
+		case o.present:
+			ok++
+		default:
+			none++
+		}
+	}
+	return ok, errc, none
+}
+
+// Iterate the present values of opts with their index in opts, errors and empty elements are skipped:
+//
+//	for i, v := range SeqIndexed(batch) { ... }
+func SeqIndexed[T any](opts []Optional[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, o := range opts {
+			if o.IsError() || !o.present {
+				continue
+			}
+			if !yield(i, o.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Aggregate a batch into one Optional, with the following precedence:
+//  1. If any element is an error, an error joining all errors (errors.Join, one message per line)
+//     with the code of the first failing element.
+//  2. Otherwise the first element holding a value.
+//  3. Otherwise an empty Optional.
+func AggregateCodes[T any](opts []Optional[T]) Optional[T] {
+	var errs []error
+	var code uint32
+	failed := false
+	first := -1
+	for i, o := range opts {
+		switch {
+		case o.IsError():
+			if !failed {
+				code, failed = o.ErrorCode, true
+			}
+			if o.Error != nil {
+				errs = append(errs, o.Error)
+			}
+		case o.present && first < 0:
+			first = i
+		}
+	}
+	switch {
+	case failed:
+		return Optional[T]{Error: errors.Join(errs...), ErrorCode: code}
+	case first >= 0:
+		return opts[first]
+	default:
+		return None[T]()
+	}
+}
+
+// Receive from ch until it is closed and partition the results into values and errors.
+// Empty Optionals are skipped. Blocks until ch is closed, the sender must close it.
+func DrainChan[T any](ch <-chan Optional[T]) (values []T, errs []error) {
+	for o := range ch {
+		switch {
+		case o.IsError():
+			errs = append(errs, o.Error)
+		case o.present:
+			values = append(values, o.Value)
+		}
+	}
+	return values, errs
+}
+
+// Find the first present value satisfying pred, empty if there is none.
+// Errors are skipped, an error before a match does not stop the search.
+func FindFirst[T any](opts []Optional[T], pred func(T) bool) Optional[T] {
+	for _, o := range opts {
+		if !o.IsError() && o.present && pred(o.Value) {
+			return o
+		}
+	}
+	return None[T]()
+}
+
+// Sum the present values, empty elements are ignored. Returns the first error encountered.
+// A batch without values (all empty or no elements) sums to a present Ok(0).
+func SumOpt[T Number](opts []Optional[T]) Optional[T] {
+	var sum T
+	for _, o := range opts {
+		if o.IsError() {
+			return Forward[T](o)
+		}
+		if o.present {
+			sum += o.Value
+		}
+	}
+	return Ok(sum)
+}
+
+// Average the present values, empty elements are ignored. Returns the first error encountered.
+// A batch without values has no average and returns an empty Optional.
+func AvgOpt[T Number](opts []Optional[T]) Optional[float64] {
+	var sum float64
+	count := 0
+	for _, o := range opts {
+		if o.IsError() {
+			return Forward[float64](o)
+		}
+		if o.present {
+			sum += float64(o.Value)
+			count++
+		}
+	}
+	if count == 0 {
+		return None[float64]()
+	}
+	return Ok(sum / float64(count))
+}
+
+// Partition opts into Optionals holding a value and the rest (errors and empty Optionals),
+// keeping the Optionals themselves and their order, e.g. to re-process the failures.
+func SplitOk[T any](opts []Optional[T]) (present []Optional[T], failed []Optional[T]) {
+	for _, o := range opts {
+		if o.IsSomeStrict() {
+			present = append(present, o)
+		} else {
+			failed = append(failed, o)
+		}
+	}
+	return present, failed
+}
+
+// Remove repeated values, keeping the first occurrence and the order. Errors and empty elements are all kept.
+// A present zero value is a value like any other, so only repeated zero values are removed.
+func Dedup[T comparable](opts []Optional[T]) []Optional[T] {
+	seen := make(map[T]struct{}, len(opts))
+	out := make([]Optional[T], 0, len(opts))
+	for _, o := range opts {
+		if !o.IsError() && o.present {
+			if _, dup := seen[o.Value]; dup {
+				continue
+			}
+			seen[o.Value] = struct{}{}
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// Index the present values of opts by keyFn, stopping at the first error. Empty elements are skipped.
+// If two values map to the same key, an error with DUPLICATE_KEY_ERROR_CODE is returned
+// naming the key and both values with their indices.
+func ToMap[T any, K comparable](opts []Optional[T], keyFn func(T) K) Optional[map[K]T] {
+	m := make(map[K]T, len(opts))
+	indices := make(map[K]int, len(opts))
+	for i, o := range opts {
+		if o.IsError() {
+			return Forward[map[K]T](o)
+		}
+		if !o.present {
+			continue
+		}
+		k := keyFn(o.Value)
+		if j, ok := indices[k]; ok {
+			return codeErr[map[K]T](DUPLICATE_KEY_ERROR_CODE, fmt.Errorf("ToMap: duplicate key %v for %v (index %d) and %v (index %d)", k, m[k], j, o.Value, i))
+		}
+		m[k] = o.Value
+		indices[k] = i
+	}
+	return Ok(m)
+}
+
+// Pull present values from seq until n are collected or seq ends, then stop iterating.
+// Errors and empty elements are skipped, fewer than n values are returned if seq ends early.
+// A negative n returns an error with ARGUMENT_ERROR_CODE. n is only an upper bound, memory grows with the values collected.
+func TakeN[T any](seq iter.Seq[Optional[T]], n int) Optional[[]T] {
+	if n < 0 {
+		return codeErr[[]T](ARGUMENT_ERROR_CODE, fmt.Errorf("TakeN: invalid count %d", n))
+	}
+	values := make([]T, 0, min(n, 64))
+	if n == 0 {
+		return Ok(values)
+	}
+	for o := range seq {
+		if o.IsError() || !o.present {
+			continue
+		}
+		values = append(values, o.Value)
+		if len(values) == n {
+			break
+		}
+	}
+	return Ok(values)
+}
+
+// Check a batch of side effects: the first error (with its code), or OkVoid() if all succeeded.
+// Empty elements count as success, an empty batch returns OkVoid().
+func CollectVoid(opts []Optional[Void]) Optional[Void] {
+	for _, o := range opts {
+		if o.IsError() {
+			return o
+		}
+	}
+	return OkVoid()
+}
+
+// Like CollectVoid, but joins all errors for reporting, see AggregateCodes.
+func CollectVoidJoined(opts []Optional[Void]) Optional[Void] {
+	if o := AggregateCodes(opts); o.IsError() {
+		return o
+	}
+	return OkVoid()
+}
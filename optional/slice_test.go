@@ -0,0 +1,240 @@
+package optional
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSlice(t *testing.T) {
+	sum := func(acc int, v int) Optional[int] { return Ok(acc + v) }
+
+	t.Run("FoldM folds all values", func(t *testing.T) {
+		opt := FoldM([]Optional[int]{Ok(1), Ok(2), None[int](), Ok(3)}, 10, sum)
+		if opt.IsError() || opt.Value != 16 { t.Fatalf("expected 16, got %v", opt) }
+	})
+
+	t.Run("FoldM stops at input error", func(t *testing.T) {
+		calls := 0
+		opt := FoldM([]Optional[int]{Ok(1), CodeErr[int](5, "bad input"), Ok(3)}, 0, func(acc int, v int) Optional[int] { calls++; return Ok(acc + v) })
+		if opt.ErrorCode != 5 || opt.Error.Error() != "bad input" { t.Fatalf("expected input error, got %v", opt) }
+		if calls != 1 { t.Fatalf("expected f to run once, ran %d times", calls) }
+	})
+
+	t.Run("FoldM stops at f error", func(t *testing.T) {
+		cause := errors.New("overflow")
+		opt := FoldM([]Optional[int]{Ok(1), Ok(2), Ok(3)}, 0, func(acc int, v int) Optional[int] {
+			if v == 2 { return CodeErr[int](7, cause) }
+			return Ok(acc + v)
+		})
+		if opt.ErrorCode != 7 || !errors.Is(opt.Error, cause) { t.Fatalf("expected f error, got %v", opt) }
+	})
+
+	t.Run("Chunk splits with remainder", func(t *testing.T) {
+		chunks := Chunk([]Optional[int]{Ok(1), Ok(2), Err[int]("e"), Ok(4), Ok(5)}, 2)
+		if len(chunks) != 3 { t.Fatalf("expected 3 chunks, got %d", len(chunks)) }
+		if len(chunks[0]) != 2 || len(chunks[1]) != 2 || len(chunks[2]) != 1 { t.Fatalf("unexpected chunk sizes: %v", chunks) }
+		if !chunks[1][0].IsError() || chunks[2][0].Value != 5 { t.Fatalf("unexpected chunk content: %v", chunks) }
+		if chunks := Chunk([]Optional[int]{Ok(1), Ok(2)}, math.MaxInt); len(chunks) != 1 || len(chunks[0]) != 2 { t.Fatalf("expected a single chunk for a size larger than the input, got %v", chunks) }
+	})
+
+	t.Run("Chunk returns nil only for invalid sizes", func(t *testing.T) {
+		if Chunk([]Optional[int]{Ok(1)}, 0) != nil { t.Fatalf("expected nil for size 0") }
+		if Chunk([]Optional[int]{Ok(1)}, -3) != nil { t.Fatalf("expected nil for negative size") }
+		if chunks := Chunk([]Optional[int]{}, 2); chunks == nil || len(chunks) != 0 { t.Fatalf("expected empty non-nil result for empty input, got %v", chunks) }
+		if opt := CollectChunks([]Optional[int]{Ok(1)}, 0); opt.ErrorCode != ARGUMENT_ERROR_CODE { t.Fatalf("expected ARGUMENT_ERROR_CODE from CollectChunks, got %v", opt) }
+	})
+
+	t.Run("CollectChunks collects values", func(t *testing.T) {
+		opt := CollectChunks([]Optional[int]{Ok(1), None[int](), Ok(2), Ok(3)}, 2)
+		if opt.IsError() { t.Fatalf("unexpected error: %v", opt.Error) }
+		if len(opt.Value) != 2 || len(opt.Value[0]) != 2 || opt.Value[1][0] != 3 { t.Fatalf("unexpected chunks: %v", opt.Value) }
+	})
+
+	t.Run("CollectChunks stops at error", func(t *testing.T) {
+		opt := CollectChunks([]Optional[int]{Ok(1), Ok(2), CodeErr[int](4, "bad")}, 2)
+		if opt.ErrorCode != 4 { t.Fatalf("expected code 4, got %v", opt) }
+	})
+
+	t.Run("CollectChunks rejects invalid size", func(t *testing.T) {
+		opt := CollectChunks([]Optional[int]{Ok(1)}, 0)
+		if opt.ErrorCode != ARGUMENT_ERROR_CODE { t.Fatalf("expected ARGUMENT_ERROR_CODE, got %v", opt) }
+	})
+
+	t.Run("Dump renders mixed batch", func(t *testing.T) {
+		got := Dump([]Optional[int]{Ok(42), CodeErr[int](123, "boom"), None[int](), Ok(0), Err[int]("plain")})
+		want := "[0] ok: 42\n[1] err(123): boom\n[2] none\n[3] ok: 0\n[4] err: plain"
+		if got != want { t.Fatalf("unexpected dump:\n%s\nwant:\n%s", got, want) }
+		if Dump[int](nil) != "" { t.Fatalf("expected empty dump for empty batch") }
+	})
+
+	t.Run("FilterMap keeps present results", func(t *testing.T) {
+		calls := 0
+		got := FilterMap([]string{"1", "x", "", "0", "3"}, func(s string) Optional[int] {
+			calls++
+			if s == "" { return None[int]() }
+			return GoOpt(strconv.Atoi(s))
+		})
+		if calls != 5 { t.Fatalf("expected f to run for every element, ran %d times", calls) }
+		if !slices.Equal(got, []int{1, 0, 3}) { t.Fatalf("expected [1 0 3], got %v", got) }
+	})
+
+	t.Run("KeepSome and DropErrors", func(t *testing.T) {
+		batch := []Optional[int]{Ok(1), Err[int]("e"), None[int](), Ok(0), GoOpt(5, errors.New("e"))}
+		kept := KeepSome(batch)
+		if Dump(kept) != "[0] ok: 1\n[1] ok: 0" { t.Fatalf("unexpected KeepSome result:\n%s", Dump(kept)) }
+		dropped := DropErrors(batch)
+		if Dump(dropped) != "[0] ok: 1\n[1] none\n[2] ok: 0" { t.Fatalf("unexpected DropErrors result:\n%s", Dump(dropped)) }
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		batch := []Optional[int]{Ok(1), Ok(0), Err[int]("e"), GoOpt(5, errors.New("e")), None[int](), Ok(-1)}
+		ok, errc, none := Stats(batch)
+		if ok != 3 || errc != 2 || none != 1 { t.Fatalf("expected 3/2/1, got %d/%d/%d", ok, errc, none) }
+		if ok+errc+none != len(batch) { t.Fatalf("counts must add up to len") }
+		if ok, errc, none := Stats[int](nil); ok+errc+none != 0 { t.Fatalf("expected zero counts for empty batch") }
+	})
+
+	t.Run("SeqIndexed yields present values", func(t *testing.T) {
+		batch := []Optional[string]{Ok("a"), Err[string]("e"), None[string](), Ok(""), Ok("c")}
+		var indices []int
+		var values []string
+		for i, v := range SeqIndexed(batch) {
+			indices = append(indices, i)
+			values = append(values, v)
+		}
+		if !slices.Equal(indices, []int{0, 3, 4}) || !slices.Equal(values, []string{"a", "", "c"}) { t.Fatalf("unexpected iteration: %v %v", indices, values) }
+		for i := range SeqIndexed(batch) {
+			if i != 0 { t.Fatalf("break must stop the iteration") }
+			break
+		}
+	})
+
+	t.Run("AggregateCodes combines failures", func(t *testing.T) {
+		opt := AggregateCodes([]Optional[int]{None[int](), CodeErr[int](11, "first"), Ok(1), CodeErr[int](12, "second")})
+		if opt.ErrorCode != 11 { t.Fatalf("expected code of first failure, got %d", opt.ErrorCode) }
+		if opt.Error.Error() != "first\nsecond" { t.Fatalf("unexpected combined message %q", opt.Error) }
+	})
+
+	t.Run("AggregateCodes returns first success", func(t *testing.T) {
+		opt := AggregateCodes([]Optional[int]{None[int](), Ok(0), Ok(2)})
+		if opt.IsError() || !opt.IsSomeStrict() || opt.Value != 0 { t.Fatalf("expected first value 0, got %v", opt) }
+		if !AggregateCodes[int](nil).IsNone() { t.Fatalf("expected none for empty batch") }
+	})
+
+	t.Run("DrainChan partitions until close", func(t *testing.T) {
+		ch := make(chan Optional[int])
+		go func() {
+			defer close(ch)
+			ch <- Ok(1)
+			ch <- Err[int]("worker failed")
+			ch <- Ok(0)
+		}()
+		values, errs := DrainChan(ch)
+		if !slices.Equal(values, []int{1, 0}) { t.Fatalf("unexpected values %v", values) }
+		if len(errs) != 1 || errs[0].Error() != "worker failed" { t.Fatalf("unexpected errors %v", errs) }
+	})
+
+	t.Run("FindFirst", func(t *testing.T) {
+		even := func(i int) bool { return i%2 == 0 }
+		batch := []Optional[int]{Ok(1), Err[int]("e"), None[int](), Ok(4), Ok(6)}
+		if o := FindFirst(batch, even); o.Value != 4 || !o.IsSomeStrict() { t.Fatalf("expected 4, got %v", o) }
+		if o := FindFirst([]Optional[int]{Ok(1), Err[int]("e")}, even); !o.IsNone() { t.Fatalf("expected none, got %v", o) }
+		if o := FindFirst([]Optional[int]{Ok(0)}, even); !o.IsSomeStrict() { t.Fatalf("expected present zero value") }
+	})
+
+	t.Run("SumOpt and AvgOpt", func(t *testing.T) {
+		batch := []Optional[int]{Ok(1), None[int](), Ok(2), Ok(0), Ok(3)}
+		if o := SumOpt(batch); o.Value != 6 || !o.IsSomeStrict() { t.Fatalf("expected 6, got %v", o) }
+		if o := AvgOpt(batch); o.Value != 1.5 { t.Fatalf("expected 1.5, got %v", o) }
+		if o := SumOpt([]Optional[float64]{None[float64]()}); !o.IsSomeStrict() || o.Value != 0 { t.Fatalf("expected present 0 for all-none, got %v", o.Comparable()) }
+		if o := AvgOpt([]Optional[float64]{None[float64]()}); !o.IsNone() { t.Fatalf("expected none average for all-none, got %v", o) }
+	})
+
+	t.Run("SumOpt and AvgOpt propagate first error", func(t *testing.T) {
+		batch := []Optional[int]{Ok(1), CodeErr[int](31, "metric missing"), CodeErr[int](32, "later")}
+		if o := SumOpt(batch); o.ErrorCode != 31 || o.present { t.Fatalf("expected first error without value, got %v", o.Comparable()) }
+		if o := AvgOpt(batch); o.ErrorCode != 31 { t.Fatalf("expected first error, got %v", o.Comparable()) }
+	})
+
+	t.Run("ToMap indexes values", func(t *testing.T) {
+		opt := ToMap([]Optional[string]{Ok("apple"), None[string](), Ok("banana")}, func(s string) byte { return s[0] })
+		if !opt.IsSomeStrict() || len(opt.Value) != 2 || opt.Value['b'] != "banana" { t.Fatalf("unexpected map %v", opt) }
+	})
+
+	t.Run("ToMap stops at errors and duplicate keys", func(t *testing.T) {
+		first := func(s string) byte { return s[0] }
+		if opt := ToMap([]Optional[string]{Ok("apple"), CodeErr[string](6, "e"), Ok("apple")}, first); opt.ErrorCode != 6 { t.Fatalf("expected forwarded error, got %v", opt) }
+		opt := ToMap([]Optional[string]{Ok("apple"), Ok("banana"), Ok("avocado")}, first)
+		if opt.ErrorCode != DUPLICATE_KEY_ERROR_CODE { t.Fatalf("expected DUPLICATE_KEY_ERROR_CODE, got %v (%d)", opt, opt.ErrorCode) }
+		if msg := opt.Error.Error(); !strings.Contains(msg, "apple (index 0)") || !strings.Contains(msg, "avocado (index 2)") { t.Fatalf("expected both values in message, got %q", msg) }
+	})
+
+	t.Run("TakeN stops after n values", func(t *testing.T) {
+		opts := []Optional[int]{Err[int]("e"), Ok(1), None[int](), CodeErr[int](2, "e"), Ok(2), Ok(3), Ok(4)}
+		pulled := 0
+		seq := func(yield func(Optional[int]) bool) {
+			for _, o := range opts {
+				pulled++
+				if !yield(o) { return }
+			}
+		}
+		opt := TakeN(seq, 2)
+		if !slices.Equal(opt.Value, []int{1, 2}) { t.Fatalf("expected [1 2], got %v", opt) }
+		if pulled != 5 { t.Fatalf("expected 5 elements pulled, got %d", pulled) }
+		if opt := TakeN(slices.Values(opts), 10); !slices.Equal(opt.Value, []int{1, 2, 3, 4}) { t.Fatalf("expected all values, got %v", opt) }
+		if opt := TakeN(slices.Values(opts), math.MaxInt); !slices.Equal(opt.Value, []int{1, 2, 3, 4}) { t.Fatalf("expected all values for a huge n, got %v", opt) }
+		if opt := TakeN(slices.Values(opts), -1); opt.ErrorCode != ARGUMENT_ERROR_CODE { t.Fatalf("expected ARGUMENT_ERROR_CODE, got %v", opt) }
+	})
+
+	t.Run("SplitOk keeps the Optionals", func(t *testing.T) {
+		e := CodeErr[int](3, "e")
+		present, failed := SplitOk([]Optional[int]{Ok(1), e, None[int](), Ok(0)})
+		if len(present) != 2 || present[0] != Ok(1) || present[1] != Ok(0) { t.Fatalf("unexpected present bucket %v", Dump(present)) }
+		if len(failed) != 2 || failed[0] != e || !failed[1].IsNone() { t.Fatalf("unexpected failed bucket %v", Dump(failed)) }
+	})
+
+	t.Run("ChunkSeq yields batches", func(t *testing.T) {
+		opts := []Optional[int]{Ok(1), Ok(2), Err[int]("e"), Ok(4), Ok(5), Ok(6)}
+		var sizes []int
+		for chunk := range ChunkSeq(opts, 3) { sizes = append(sizes, len(chunk)) }
+		if !slices.Equal(sizes, []int{3, 3}) { t.Fatalf("expected [3 3], got %v", sizes) }
+		sizes = nil
+		for chunk := range ChunkSeq(opts, 4) { sizes = append(sizes, len(chunk)) }
+		if !slices.Equal(sizes, []int{4, 2}) { t.Fatalf("expected [4 2], got %v", sizes) }
+		for range ChunkSeq(opts, 0) { t.Fatalf("expected no chunks for size 0") }
+	})
+
+	t.Run("CollectVoid", func(t *testing.T) {
+		if opt := CollectVoid(nil); !opt.IsSomeStrict() { t.Fatalf("expected OkVoid for empty input, got %v", opt) }
+		if opt := CollectVoid([]Optional[Void]{OkVoid(), None[Void](), OkVoid()}); !opt.IsSomeStrict() { t.Fatalf("expected OkVoid, got %v", opt) }
+		writes := []Optional[Void]{OkVoid(), CodeErr[Void](3, "disk full"), CodeErr[Void](4, "timeout")}
+		if opt := CollectVoid(writes); opt.ErrorCode != 3 || opt.Error.Error() != "disk full" { t.Fatalf("expected first error with code 3, got %v (%d)", opt, opt.ErrorCode) }
+		opt := CollectVoidJoined(writes)
+		if opt.ErrorCode != 3 || opt.Error.Error() != "disk full\ntimeout" { t.Fatalf("expected joined errors with code 3, got %q (%d)", opt.Error, opt.ErrorCode) }
+		if opt := CollectVoidJoined(nil); !opt.IsSomeStrict() { t.Fatalf("expected OkVoid for empty input, got %v", opt) }
+	})
+
+	t.Run("Dedup keeps first values, errors and empty elements", func(t *testing.T) {
+		e1, e2 := CodeErr[int](1, "e"), CodeErr[int](1, "e")
+		got := Dedup([]Optional[int]{Ok(2), e1, Ok(0), None[int](), Ok(2), e2, Ok(0), None[int](), Ok(3)})
+		if Dump(got) != Dump([]Optional[int]{Ok(2), e1, Ok(0), None[int](), e2, None[int](), Ok(3)}) { t.Fatalf("unexpected result %v", Dump(got)) }
+	})
+
+	t.Run("DiffBatches", func(t *testing.T) {
+		a := []Optional[int]{Ok(1), Ok(2), Ok(3), CodeErr[int](4, "timeout"), None[int](), Ok(6)}
+		b := []Optional[int]{Ok(1), Ok(5), CodeErr[int](4, "timeout"), Ok(3), Ok(0)}
+		want := "[1] value changed: ok: 2 -> ok: 5\n" +
+			"[2] error appeared: ok: 3 -> err(4): timeout\n" +
+			"[3] error disappeared: err(4): timeout -> ok: 3\n" +
+			"[4] state flipped: none -> ok: 0\n" +
+			"[5] removed: ok: 6"
+		if got := DiffBatches(a, b); got != want { t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want) }
+		if got := DiffBatches([]Optional[int]{CodeErr[int](4, "a")}, []Optional[int]{CodeErr[int](4, "b"), Ok(1)}); got != "[0] error changed: err(4): a -> err(4): b\n[1] added: ok: 1" { t.Fatalf("unexpected diff %q", got) }
+		if got := DiffBatches(a, slices.Clone(a)); got != "" { t.Fatalf("expected no diff, got %q", got) }
+		if got := DiffBatches([]Optional[int]{GoOpt(1, errors.New("e"))}, []Optional[int]{GoOpt(2, errors.New("e"))}); got != "" { t.Fatalf("best-effort values must be ignored, got %q", got) }
+	})
+}